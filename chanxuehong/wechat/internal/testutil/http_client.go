@@ -0,0 +1,31 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+// 测试用的辅助函数, 只在本项目的测试中使用.
+package testutil
+
+import (
+	"net/http"
+)
+
+// 创建一个把所有请求都转发到 addr(host:port) 的 http.Client.
+//  用于把请求微信服务器的接口转发到本地的 httptest.Server, 请求的 path 和 query 不变.
+func NewRedirectHttpClient(addr string) *http.Client {
+	return &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			r2 := r.Clone(r.Context())
+			r2.URL.Scheme = "http"
+			r2.URL.Host = addr
+			r2.Host = addr
+			return http.DefaultTransport.RoundTrip(r2)
+		}),
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
@@ -0,0 +1,138 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+// paybill 下载微信支付某一天的对账单和资金账单, 保存为 CSV 文件.
+//
+//  用法:
+//  paybill -appid wx... -mchid 1900000109 -apikey xxx -cert apiclient_cert.pem -key apiclient_key.pem -date 20180201 -out ./bills
+//
+//  生成的文件:
+//  bill_20180201.csv                对账单(bill_type 由 -billtype 指定)
+//  fundflow_20180201_Basic.csv      资金账单(每个 -accounttypes 指定的资金账户一个文件)
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/chanxuehong/util"
+	"github.com/chanxuehong/wechat/mch/pay"
+)
+
+var (
+	appId        = flag.String("appid", "", "公众账号ID")
+	mchId        = flag.String("mchid", "", "商户号")
+	apiKey       = flag.String("apikey", "", "API密钥")
+	certFile     = flag.String("cert", "", "商户证书文件, 下载资金账单时必须指定")
+	keyFile      = flag.String("key", "", "商户证书私钥文件, 下载资金账单时必须指定")
+	date         = flag.String("date", "", "账单日期, 格式为 20060102, 默认为昨天")
+	billType     = flag.String("billtype", "ALL", "对账单类型: ALL, SUCCESS, REFUND")
+	accountTypes = flag.String("accounttypes", pay.AccountTypeBasic, "资金账户类型, 多个用逗号分隔: Basic, Operation, Fees; 为空则不下载资金账单")
+	outDir       = flag.String("out", ".", "CSV 文件的输出目录")
+)
+
+func main() {
+	flag.Parse()
+	if *appId == "" || *mchId == "" || *apiKey == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	// 资金账单需要双向证书
+	if *accountTypes != "" && (*certFile == "" || *keyFile == "") {
+		fmt.Fprintln(os.Stderr, "-cert and -key are required to download fund flow bills, or set -accounttypes to empty")
+		os.Exit(2)
+	}
+	if *date == "" {
+		*date = time.Now().In(util.BeijingLocation).AddDate(0, 0, -1).Format("20060102")
+	}
+
+	var failed bool
+
+	// 对账单不需要证书
+	clt := pay.NewClient(*apiKey, nil)
+	req := map[string]string{
+		"appid":     *appId,
+		"mch_id":    *mchId,
+		"nonce_str": pay.NewNonceStr(),
+		"bill_date": *date,
+		"bill_type": *billType,
+	}
+	if err := download(clt.DownloadBill, req, "bill_"+*date+".csv"); err != nil {
+		fmt.Fprintln(os.Stderr, "download bill:", err)
+		failed = true
+	}
+
+	if *accountTypes != "" {
+		httpClient, err := pay.NewTLSHttpClient(*certFile, *keyFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "load certificate:", err)
+			os.Exit(1)
+		}
+		tlsClt := pay.NewClient(*apiKey, httpClient)
+
+		for _, accountType := range strings.Split(*accountTypes, ",") {
+			accountType = strings.TrimSpace(accountType)
+			req := map[string]string{
+				"appid":        *appId,
+				"mch_id":       *mchId,
+				"nonce_str":    pay.NewNonceStr(),
+				"bill_date":    *date,
+				"account_type": accountType,
+			}
+			filename := "fundflow_" + *date + "_" + accountType + ".csv"
+			if err := download(tlsClt.DownloadFundFlow, req, filename); err != nil {
+				fmt.Fprintln(os.Stderr, "download fund flow", accountType+":", err)
+				failed = true
+			}
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// 下载账单, 解析后写入 CSV 文件: 表头, 明细, 空行, 汇总的表头, 汇总.
+func download(fn func(map[string]string) ([]byte, error), req map[string]string, filename string) (err error) {
+	data, err := fn(req)
+	if err != nil {
+		return
+	}
+	bill, err := pay.ParseBillData(data)
+	if err != nil {
+		return
+	}
+
+	file, err := os.Create(filepath.Join(*outDir, filename))
+	if err != nil {
+		return
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	w := csv.NewWriter(file)
+	w.Write(bill.Header)
+	w.WriteAll(bill.Records)
+	if len(bill.SummaryHeader) > 0 {
+		w.Write(nil)
+		w.Write(bill.SummaryHeader)
+		w.Write(bill.Summary)
+	}
+	w.Flush()
+	if err = w.Error(); err != nil {
+		return
+	}
+
+	fmt.Printf("%s: %d records\n", filename, len(bill.Records))
+	return
+}
@@ -0,0 +1,186 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package pay
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chanxuehong/util"
+)
+
+// 对账单, 资金账单等表格数据, 字段值已经去掉了微信添加的前缀 "`".
+//  数据格式为: 表头, 明细(多行), 汇总的表头, 汇总.
+type BillData struct {
+	Header        []string   // 明细的表头
+	Records       [][]string // 明细
+	SummaryHeader []string   // 汇总的表头
+	Summary       []string   // 汇总
+}
+
+// 第 i 条明细的 表头 --> 字段值 集合.
+func (data *BillData) Record(i int) map[string]string {
+	m := make(map[string]string, len(data.Header))
+	for j, key := range data.Header {
+		if j < len(data.Records[i]) {
+			m[key] = data.Records[i][j]
+		}
+	}
+	return m
+}
+
+// 汇总的 表头 --> 字段值 集合.
+func (data *BillData) SummaryMap() map[string]string {
+	m := make(map[string]string, len(data.SummaryHeader))
+	for j, key := range data.SummaryHeader {
+		if j < len(data.Summary) {
+			m[key] = data.Summary[j]
+		}
+	}
+	return m
+}
+
+// 解析 DownloadBill, DownloadFundFlow 返回的数据.
+func ParseBillData(data []byte) (bill *BillData, err error) {
+	lines := splitLines(data)
+	if len(lines) == 0 {
+		err = errors.New("empty bill data")
+		return
+	}
+
+	bill = &BillData{
+		Header: strings.Split(lines[0], ","),
+	}
+	i := 1
+	for ; i < len(lines) && strings.HasPrefix(lines[i], "`"); i++ {
+		bill.Records = append(bill.Records, splitBillLine(lines[i], -1))
+	}
+	if i < len(lines) {
+		bill.SummaryHeader = strings.Split(lines[i], ",")
+		i++
+	}
+	if i < len(lines) {
+		bill.Summary = splitBillLine(lines[i], -1)
+		i++
+	}
+	if i < len(lines) {
+		err = fmt.Errorf("unexpected bill line %d: %q", i+1, lines[i])
+		bill = nil
+		return
+	}
+	return
+}
+
+// 资金账单的明细
+type FundFlowRecord struct {
+	Time          time.Time // 记账时间
+	TransactionId string    // 微信支付业务单号
+	FlowId        string    // 资金流水单号
+	BizName       string    // 业务名称
+	BizType       string    // 业务类型
+	FlowType      string    // 收支类型, 收入或者支出
+	Amount        string    // 收支金额(元)
+	Balance       string    // 账户结余(元)
+	Applicant     string    // 资金变更提交申请人
+	Remark        string    // 备注
+	BizVoucherId  string    // 业务凭证号
+}
+
+// 解析 DownloadFundFlow 返回的数据为明细列表.
+func ParseFundFlowRecords(data []byte) (records []FundFlowRecord, err error) {
+	bill, err := ParseBillData(data)
+	if err != nil {
+		return
+	}
+
+	records = make([]FundFlowRecord, len(bill.Records))
+	for i, fields := range bill.Records {
+		if len(fields) < 11 {
+			err = fmt.Errorf("the fund flow record %d has %d fields, want 11", i, len(fields))
+			records = nil
+			return
+		}
+		record := &records[i]
+		if record.Time, err = time.ParseInLocation("2006-01-02 15:04:05", fields[0], util.BeijingLocation); err != nil {
+			records = nil
+			return
+		}
+		record.TransactionId = fields[1]
+		record.FlowId = fields[2]
+		record.BizName = fields[3]
+		record.BizType = fields[4]
+		record.FlowType = fields[5]
+		record.Amount = fields[6]
+		record.Balance = fields[7]
+		record.Applicant = fields[8]
+		record.Remark = fields[9]
+		record.BizVoucherId = fields[10]
+	}
+	return
+}
+
+// 订单评价
+type Comment struct {
+	Time          time.Time // 评论时间
+	TransactionId string    // 支付订单号
+	Stars         int       // 评论星级
+	Content       string    // 评论内容
+}
+
+// 解析 BatchQueryComment 返回的数据.
+//  offset 为下一页的 offset; 第一行是 offset, 之后每行一条评论.
+func ParseComments(data []byte) (offset int64, comments []Comment, err error) {
+	lines := splitLines(data)
+	if len(lines) == 0 {
+		err = errors.New("empty comment data")
+		return
+	}
+	if offset, err = strconv.ParseInt(strings.TrimPrefix(lines[0], "`"), 10, 64); err != nil {
+		err = fmt.Errorf("invalid offset line: %q", lines[0])
+		return
+	}
+
+	comments = make([]Comment, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		fields := splitBillLine(line, 4) // 评论内容可能包含逗号
+		if len(fields) != 4 {
+			err = fmt.Errorf("invalid comment line: %q", line)
+			return 0, nil, err
+		}
+
+		var comment Comment
+		if comment.Time, err = time.ParseInLocation("2006-01-02 15:04:05", fields[0], util.BeijingLocation); err != nil {
+			return 0, nil, err
+		}
+		comment.TransactionId = fields[1]
+		if comment.Stars, err = strconv.Atoi(fields[2]); err != nil {
+			return 0, nil, fmt.Errorf("invalid comment stars: %q", fields[2])
+		}
+		comment.Content = fields[3]
+		comments = append(comments, comment)
+	}
+	return
+}
+
+// 按行分割, 去掉 BOM, 行尾的 \r 和空行.
+func splitLines(data []byte) (lines []string) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return
+}
+
+// 分割以 "`" 为前缀的字段, 字段之间以 ",`" 分隔, 以便字段中可以包含逗号; n 同 strings.SplitN.
+func splitBillLine(line string, n int) []string {
+	return strings.SplitN(strings.TrimPrefix(line, "`"), ",`", n)
+}
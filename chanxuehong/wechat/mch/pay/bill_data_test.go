@@ -0,0 +1,93 @@
+package pay
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/chanxuehong/util"
+)
+
+func TestParseBillData(t *testing.T) {
+	data := "\xef\xbb\xbf记账时间,微信支付业务单号,资金流水单号,业务名称,业务类型,收支类型,收支金额（元）,账户结余（元）,资金变更提交申请人,备注,业务凭证号\r\n" +
+		"`2018-02-01 04:21:23,`50000305742018020103387128253,`1900009231201802015884652186,`退款,`退款,`支出,`0.02,`0.17,`system,`缺货,`REF4200000068201801293084726067\r\n" +
+		"`2018-02-01 04:20:01,`4200000068201801293084726067,`1900009231201802012345678901,`交易,`交易,`收入,`0.19,`0.19,`system,`,`\r\n" +
+		"资金流水总笔数,收入笔数,收入金额,支出笔数,支出金额\r\n" +
+		"`2,`1,`0.19,`1,`0.02\r\n"
+
+	bill, err := ParseBillData([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bill.Header) != 11 || len(bill.Records) != 2 || len(bill.Summary) != 5 {
+		t.Fatalf("ParseBillData: %+v", bill)
+	}
+	if have := bill.Record(0)["备注"]; have != "缺货" {
+		t.Errorf("Record(0)[备注]: have %q, want %q", have, "缺货")
+	}
+	if have := bill.SummaryMap()["支出金额"]; have != "0.02" {
+		t.Errorf("SummaryMap()[支出金额]: have %q, want %q", have, "0.02")
+	}
+
+	records, err := ParseFundFlowRecords([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records[1].FlowType != "收入" || records[1].Amount != "0.19" || records[1].Remark != "" {
+		t.Errorf("ParseFundFlowRecords: %+v", records[1])
+	}
+}
+
+func TestParseComments(t *testing.T) {
+	data := "100\n" +
+		"`2017-07-01 10:00:05,`1001690740201411100005734289,`5,`赞,很好\n" +
+		"`2017-07-01 11:00:05,`1001690740201411100005734278,`3,`\n"
+
+	offset, comments, err := ParseComments([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset != 100 || len(comments) != 2 {
+		t.Fatalf("ParseComments: offset %d, %+v", offset, comments)
+	}
+	if comments[0].Stars != 5 || comments[0].Content != "赞,很好" || comments[1].Content != "" {
+		t.Errorf("ParseComments: %+v", comments)
+	}
+}
+
+// 资金账单总是用 HMAC-SHA256 签名, 沙箱 Client 直接返回错误
+func TestDownloadFundFlow(t *testing.T) {
+	stub := newStubAPI()
+	defer stub.Close()
+
+	stub.mux.HandleFunc("/pay/downloadfundflow", func(w http.ResponseWriter, r *http.Request) {
+		req, err := util.ParseXMLToMap(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		if req["sign_type"] != SignTypeHMACSHA256 {
+			t.Errorf("sign_type: have %q, want %q", req["sign_type"], SignTypeHMACSHA256)
+		}
+		if err = CheckSignature(req, signTestAPIKey, SignTypeHMACSHA256); err != nil {
+			t.Error(err)
+		}
+		w.Write([]byte("资金流水总笔数,收入笔数,收入金额,支出笔数,支出金额\r\n`0,`0,`0.00,`0,`0.00\r\n"))
+	})
+
+	clt := NewClient(signTestAPIKey, stub.HttpClient())
+	req := map[string]string{
+		"appid":        "wx2421b1c4370ec43b",
+		"mch_id":       "10000100",
+		"nonce_str":    NewNonceStr(),
+		"bill_date":    "20180201",
+		"account_type": AccountTypeBasic,
+		"sign_type":    SignTypeMD5,
+	}
+	if _, err := clt.DownloadFundFlow(req); err != nil {
+		t.Fatal(err)
+	}
+
+	delete(req, "sign")
+	if _, err := NewSandboxClient("10000100", signTestAPIKey, stub.HttpClient()).DownloadFundFlow(req); err == nil {
+		t.Error("DownloadFundFlow with a sandbox client: want error")
+	}
+}
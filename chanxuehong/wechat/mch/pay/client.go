@@ -18,25 +18,56 @@ import (
 
 type Client struct {
 	apiKey     string
+	signType   string
 	httpClient *http.Client
+
+	sandbox *sandbox // 仿真测试系统的状态, 非沙箱模式为 nil
 }
 
-// 创建一个新的 Client.
+// 创建一个新的 Client, 签名类型为 SignTypeMD5.
 //  如果 httpClient == nil 则默认用 http.DefaultClient.
 func NewClient(apiKey string, httpClient *http.Client) *Client {
+	return NewClientWithSignType(apiKey, SignTypeMD5, httpClient)
+}
+
+// 创建一个新的 Client, 指定签名类型.
+//  signType: SignTypeMD5 或 SignTypeHMACSHA256, 为空时默认为 SignTypeMD5.
+//  如果 httpClient == nil 则默认用 http.DefaultClient.
+func NewClientWithSignType(apiKey, signType string, httpClient *http.Client) *Client {
+	switch signType {
+	case "":
+		signType = SignTypeMD5
+	case SignTypeMD5, SignTypeHMACSHA256:
+	default:
+		panic("pay: unsupported sign_type: " + signType)
+	}
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
 
 	return &Client{
 		apiKey:     apiKey,
+		signType:   signType,
 		httpClient: httpClient,
 	}
 }
 
 // 微信支付通用请求方法.
+//  如果 req 没有 sign 参数, 则按照 Client 的签名类型自动签名(会修改 req).
 //  注意: err == nil 表示协议状态都为 SUCCESS.
 func (clt *Client) PostXML(url string, req map[string]string) (resp map[string]string, err error) {
+	return clt.postXML(url, req, true)
+}
+
+// respSignRequired 为 false 时, 回包没有 sign 参数则不校验签名, 用于企业付款等回包不带签名的接口.
+func (clt *Client) postXML(url string, req map[string]string, respSignRequired bool) (resp map[string]string, err error) {
+	if url, err = clt.apiURL(url); err != nil {
+		return
+	}
+	if err = clt.signRequest(req); err != nil {
+		return
+	}
+
 	bodyBuf := textBufferPool.Get().(*bytes.Buffer)
 	bodyBuf.Reset()
 	defer textBufferPool.Put(bodyBuf)
@@ -75,14 +106,10 @@ func (clt *Client) PostXML(url string, req map[string]string) (resp map[string]s
 	}
 
 	// 认证签名
-	signature1, ok := resp["sign"]
-	if !ok {
-		err = errors.New("no sign parameter")
+	if _, ok := resp["sign"]; !ok && !respSignRequired {
 		return
 	}
-	signature2 := Sign(resp, clt.apiKey, nil)
-	if signature1 != signature2 {
-		err = fmt.Errorf("check signature failed, \r\ninput: %q, \r\nlocal: %q", signature1, signature2)
+	if err = clt.checkResponseSignature(req, resp); err != nil {
 		return
 	}
 	return
@@ -0,0 +1,43 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package pay
+
+import (
+	"errors"
+)
+
+const (
+	// 资金账单的资金账户类型, account_type
+	AccountTypeBasic     = "Basic"     // 基本账户
+	AccountTypeOperation = "Operation" // 运营账户
+	AccountTypeFees      = "Fees"      // 手续费账户
+)
+
+// 下载资金账单, 每次只能下载一个资金账户类型(account_type, 见 AccountTypeXXX)的账单.
+//  返回的数据可以用 ParseBillData 或者 ParseFundFlowRecords 解析.
+//  NOTE: 请求需要双向证书; 该接口只支持 HMAC-SHA256 签名, 不管 Client 的签名类型和 req 中的 sign_type 都用 HMAC-SHA256;
+//  仿真测试系统不支持该接口, 沙箱 Client 直接返回错误.
+func (clt *Client) DownloadFundFlow(req map[string]string) (data []byte, err error) {
+	if clt.sandbox != nil {
+		err = errors.New("pay/downloadfundflow is not supported by the sandbox")
+		return
+	}
+	req["sign_type"] = SignTypeHMACSHA256
+	return clt.downloadData("https://api.mch.weixin.qq.com/pay/downloadfundflow", req)
+}
+
+// 拉取订单评价数据, 用 offset 和 limit 分页.
+//  返回的数据可以用 ParseComments 解析, 下一页的 offset 为返回的 offset.
+//  NOTE: 请求需要双向证书; 该接口只支持 HMAC-SHA256 签名, 不管 Client 的签名类型和 req 中的 sign_type 都用 HMAC-SHA256;
+//  仿真测试系统不支持该接口, 沙箱 Client 直接返回错误.
+func (clt *Client) BatchQueryComment(req map[string]string) (data []byte, err error) {
+	if clt.sandbox != nil {
+		err = errors.New("billcommentsp/batchquerycomment is not supported by the sandbox")
+		return
+	}
+	req["sign_type"] = SignTypeHMACSHA256
+	return clt.downloadData("https://api.mch.weixin.qq.com/billcommentsp/batchquerycomment", req)
+}
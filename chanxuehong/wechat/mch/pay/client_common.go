@@ -52,7 +52,20 @@ func (clt *Client) ShortURL(req map[string]string) (resp map[string]string, err
 }
 
 // 下载对账单.
+//  返回的数据可以用 ParseBillData 解析.
 func (clt *Client) DownloadBill(req map[string]string) (data []byte, err error) {
+	return clt.downloadData("https://api.mch.weixin.qq.com/pay/downloadbill", req)
+}
+
+// 下载文本数据, 失败时微信服务器返回 XML 格式的错误信息.
+func (clt *Client) downloadData(url string, req map[string]string) (data []byte, err error) {
+	if url, err = clt.apiURL(url); err != nil {
+		return
+	}
+	if err = clt.signRequest(req); err != nil {
+		return
+	}
+
 	bodyBuf := textBufferPool.Get().(*bytes.Buffer)
 	bodyBuf.Reset()
 	defer textBufferPool.Put(bodyBuf)
@@ -61,7 +74,6 @@ func (clt *Client) DownloadBill(req map[string]string) (data []byte, err error)
 		return
 	}
 
-	url := "https://api.mch.weixin.qq.com/pay/downloadbill"
 	httpResp, err := clt.httpClient.Post(url, "text/xml; charset=utf-8", bodyBuf)
 	if err != nil {
 		return
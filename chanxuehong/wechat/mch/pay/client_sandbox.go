@@ -0,0 +1,139 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package pay
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/chanxuehong/util"
+)
+
+const apiHost = "https://api.mch.weixin.qq.com/"
+
+// 仿真测试系统(沙箱)的状态
+type sandbox struct {
+	mchId string
+
+	mutex   sync.Mutex
+	signKey string // 缓存的 sandbox_signkey
+}
+
+// 创建一个新的仿真测试系统(沙箱)的 Client.
+//  沙箱模式下, 接口地址改写为 https://api.mch.weixin.qq.com/sandboxnew/..., 请求和回包的签名密钥为
+//  getsignkey 获取的 sandbox_signkey(第一次请求时获取并缓存), 签名类型只支持 SignTypeMD5.
+//  仿真测试系统没有的接口(不在 api.mch.weixin.qq.com 下的接口, 比如 GetPublicKey)直接返回错误.
+//  NewNativeCallbackHandler 用该 Client 时, 回调请求的校验和回复的签名也用 sandbox_signkey.
+//  mchId:  商户号, 获取 sandbox_signkey 需要
+//  apiKey: 商户平台的 API密钥, 用于获取 sandbox_signkey
+//  如果 httpClient == nil 则默认用 http.DefaultClient.
+func NewSandboxClient(mchId, apiKey string, httpClient *http.Client) *Client {
+	clt := NewClient(apiKey, httpClient)
+	clt.sandbox = &sandbox{
+		mchId: mchId,
+	}
+	return clt
+}
+
+// 是否为仿真测试系统(沙箱)的 Client.
+func (clt *Client) IsSandbox() bool {
+	return clt.sandbox != nil
+}
+
+// 获取仿真测试系统的签名密钥 sandbox_signkey, 获取成功后缓存, 之后不再请求微信服务器.
+func (clt *Client) SandboxSignKey() (signKey string, err error) {
+	if clt.sandbox == nil {
+		err = errors.New("not a sandbox client")
+		return
+	}
+
+	sb := clt.sandbox
+	sb.mutex.Lock()
+	defer sb.mutex.Unlock()
+
+	if sb.signKey != "" {
+		signKey = sb.signKey
+		return
+	}
+	if signKey, err = clt.getSandboxSignKey(); err != nil {
+		return
+	}
+	sb.signKey = signKey
+	return
+}
+
+// 请求 getsignkey 接口, 该接口用商户平台的 API密钥 签名.
+func (clt *Client) getSandboxSignKey() (signKey string, err error) {
+	req := make(map[string]string, 3)
+	req["mch_id"] = clt.sandbox.mchId
+	req["nonce_str"] = NewNonceStr()
+	req["sign"] = SignMD5(req, clt.apiKey)
+
+	bodyBuf := textBufferPool.Get().(*bytes.Buffer)
+	bodyBuf.Reset()
+	defer textBufferPool.Put(bodyBuf)
+
+	if err = util.FormatMapToXML(bodyBuf, req); err != nil {
+		return
+	}
+
+	httpResp, err := clt.httpClient.Post(apiHost+"sandboxnew/pay/getsignkey", "text/xml; charset=utf-8", bodyBuf)
+	if err != nil {
+		return
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("http.Status: %s", httpResp.Status)
+		return
+	}
+
+	resp, err := util.ParseXMLToMap(httpResp.Body)
+	if err != nil {
+		return
+	}
+	if resp["return_code"] != ReturnCodeSuccess {
+		err = &Error{
+			ReturnCode: resp["return_code"],
+			ReturnMsg:  resp["return_msg"],
+		}
+		return
+	}
+	if signKey = resp["sandbox_signkey"]; signKey == "" {
+		err = errors.New("no sandbox_signkey parameter")
+		return
+	}
+	return
+}
+
+// 签名用的密钥, 沙箱模式下为 sandbox_signkey, 否则为 API密钥.
+func (clt *Client) signKey() (string, error) {
+	if clt.sandbox == nil {
+		return clt.apiKey, nil
+	}
+	return clt.SandboxSignKey()
+}
+
+// 沙箱模式下把接口地址改写为仿真测试系统的地址, 否则原样返回.
+//  仿真测试系统只有 api.mch.weixin.qq.com 的接口, 沙箱模式下其他域名(比如 fraud.mch.weixin.qq.com)的接口返回错误,
+//  避免请求发送到正式环境.
+func (clt *Client) apiURL(url string) (string, error) {
+	if clt.sandbox == nil {
+		return url, nil
+	}
+	if !strings.HasPrefix(url, apiHost) {
+		return "", errors.New("the sandbox does not support " + url)
+	}
+	path := url[len(apiHost):]
+	if path == "secapi/pay/refund" {
+		path = "pay/refund" // 仿真测试系统的申请退款不需要证书
+	}
+	return apiHost + "sandboxnew/" + path, nil
+}
@@ -0,0 +1,56 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package pay
+
+// 获取 Client 的签名类型, SignTypeMD5 或 SignTypeHMACSHA256.
+func (clt *Client) SignType() string {
+	if clt.signType == "" {
+		return SignTypeMD5
+	}
+	return clt.signType
+}
+
+// 如果 req 没有 sign 参数, 则用 Client 的签名类型补全 sign_type 和 sign 参数;
+// 如果 req 已经有 sign 参数, 则认为调用者已经签名, 不做任何处理.
+//  NOTE: 为了兼容不支持 sign_type 参数的接口, SignTypeMD5 时不添加 sign_type 参数.
+func (clt *Client) signRequest(req map[string]string) (err error) {
+	if _, ok := req["sign"]; ok {
+		return
+	}
+
+	signType, ok := req["sign_type"]
+	if !ok {
+		signType = clt.SignType()
+		if signType != SignTypeMD5 {
+			req["sign_type"] = signType
+		}
+	}
+
+	key, err := clt.signKey()
+	if err != nil {
+		return
+	}
+	signature, err := SignWithType(req, key, signType)
+	if err != nil {
+		return
+	}
+	req["sign"] = signature
+	return
+}
+
+// 校验回包的签名.
+//  回包有 sign_type 参数则以回包的为准, 否则用请求的 sign_type, 都没有则为 SignTypeMD5.
+func (clt *Client) checkResponseSignature(req, resp map[string]string) (err error) {
+	signType, ok := resp["sign_type"]
+	if !ok {
+		signType = req["sign_type"]
+	}
+	key, err := clt.signKey()
+	if err != nil {
+		return
+	}
+	return CheckSignature(resp, key, signType)
+}
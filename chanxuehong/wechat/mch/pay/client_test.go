@@ -0,0 +1,56 @@
+package pay
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/chanxuehong/util"
+	"github.com/chanxuehong/wechat/internal/testutil"
+)
+
+// 本地模拟的商户平台接口
+type stubAPI struct {
+	*httptest.Server
+	mux *http.ServeMux
+}
+
+func newStubAPI() *stubAPI {
+	mux := http.NewServeMux()
+	return &stubAPI{
+		Server: httptest.NewServer(mux),
+		mux:    mux,
+	}
+}
+
+// 处理 path 的请求: 用 key 校验请求的签名, 调用 fn, 回包用 key 和请求的 sign_type 签名.
+//  key 为空时不校验请求的签名, 回包也不签名; fn 返回的 return_code 为空时补全为 SUCCESS.
+func (stub *stubAPI) Handle(path, key string, fn func(req map[string]string) map[string]string) {
+	stub.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		req, err := util.ParseXMLToMap(r.Body)
+		if err != nil {
+			writeReply(w, ReturnCodeFail, err.Error())
+			return
+		}
+		if key != "" {
+			if err = CheckSignature(req, key, req["sign_type"]); err != nil {
+				writeReply(w, ReturnCodeFail, "签名错误")
+				return
+			}
+		}
+
+		resp := fn(req)
+		if resp["return_code"] == "" {
+			resp["return_code"] = ReturnCodeSuccess
+		}
+		if key != "" && resp["return_code"] == ReturnCodeSuccess {
+			resp["nonce_str"] = NewNonceStr()
+			resp["sign"], _ = SignWithType(resp, key, req["sign_type"])
+		}
+		util.FormatMapToXML(w, resp)
+	})
+}
+
+// 把发往 api.mch.weixin.qq.com 的请求转发到 stub 的 http.Client.
+func (stub *stubAPI) HttpClient() *http.Client {
+	return testutil.NewRedirectHttpClient(stub.Listener.Addr().String())
+}
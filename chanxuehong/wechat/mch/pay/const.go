@@ -14,3 +14,16 @@ const (
 	ResultCodeSuccess = "SUCCESS"
 	ResultCodeFail    = "FAIL"
 )
+
+const (
+	SignTypeMD5        = "MD5"
+	SignTypeHMACSHA256 = "HMAC-SHA256"
+)
+
+const (
+	// 交易类型
+	TradeTypeJSAPI  = "JSAPI"  // 公众号支付
+	TradeTypeNative = "NATIVE" // 原生扫码支付
+	TradeTypeAPP    = "APP"    // app支付
+	TradeTypeMWEB   = "MWEB"   // H5支付
+)
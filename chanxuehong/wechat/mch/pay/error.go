@@ -18,3 +18,13 @@ type Error struct {
 func (e *Error) Error() string {
 	return fmt.Sprintf("return_code: %q, return_msg: %q", e.ReturnCode, e.ReturnMsg)
 }
+
+// 业务结果 result_code 为 FAIL 时的错误
+type ResultError struct {
+	ErrCode    string `xml:"err_code"     json:"err_code"`
+	ErrCodeDes string `xml:"err_code_des" json:"err_code_des"`
+}
+
+func (e *ResultError) Error() string {
+	return fmt.Sprintf("err_code: %q, err_code_des: %q", e.ErrCode, e.ErrCodeDes)
+}
@@ -5,8 +5,73 @@
 
 package pay
 
+const (
+	// 企业付款的校验用户姓名选项, check_name
+	CheckNameNoCheck    = "NO_CHECK"    // 不校验真实姓名
+	CheckNameForceCheck = "FORCE_CHECK" // 强校验真实姓名(未实名认证的用户会校验失败, 无法转账), 需要填写 re_user_name
+)
+
+const (
+	// 红包状态, gethbinfo 返回的 status
+	RedPackStatusSending   = "SENDING"   // 发放中
+	RedPackStatusSent      = "SENT"      // 已发放待领取
+	RedPackStatusFailed    = "FAILED"    // 发放失败
+	RedPackStatusReceived  = "RECEIVED"  // 已领取
+	RedPackStatusRefunding = "RFUND_ING" // 退款中(微信的拼写就是 RFUND_ING)
+	RedPackStatusRefund    = "REFUND"    // 已退款
+)
+
 // 红包发放API.
 //  NOTE: 请求需要双向证书
 func (clt *Client) SendRedPack(req map[string]string) (resp map[string]string, err error) {
-	return clt.PostXML("https://api.mch.weixin.qq.com/mmpaymkttransfers/sendredpack", req)
+	return clt.postMMPayXML("https://api.mch.weixin.qq.com/mmpaymkttransfers/sendredpack", req, true)
+}
+
+// 裂变红包发放API.
+//  NOTE: 请求需要双向证书
+func (clt *Client) SendGroupRedPack(req map[string]string) (resp map[string]string, err error) {
+	return clt.postMMPayXML("https://api.mch.weixin.qq.com/mmpaymkttransfers/sendgroupredpack", req, true)
+}
+
+// 查询红包记录API, req 中的 bill_type 固定为 MCHT.
+//  NOTE: 请求需要双向证书
+func (clt *Client) GetHBInfo(req map[string]string) (resp map[string]string, err error) {
+	if _, ok := req["bill_type"]; !ok {
+		req["bill_type"] = "MCHT"
+	}
+	return clt.postMMPayXML("https://api.mch.weixin.qq.com/mmpaymkttransfers/gethbinfo", req, false)
+}
+
+// 企业付款API(付款到用户零钱), 校验用户姓名选项见 CheckNameXXX.
+//  NOTE: 请求需要双向证书; 参数名为 mch_appid, mchid 而不是 appid, mch_id.
+func (clt *Client) Transfers(req map[string]string) (resp map[string]string, err error) {
+	return clt.postMMPayXML("https://api.mch.weixin.qq.com/mmpaymkttransfers/promotion/transfers", req, false)
+}
+
+// 查询企业付款API.
+//  NOTE: 请求需要双向证书
+func (clt *Client) GetTransferInfo(req map[string]string) (resp map[string]string, err error) {
+	return clt.postMMPayXML("https://api.mch.weixin.qq.com/mmpaymkttransfers/gettransferinfo", req, false)
+}
+
+// 红包, 企业付款等接口只支持 MD5 签名并且不支持 sign_type 参数.
+//  如果 req 没有 sign 参数, 则不管 Client 的签名类型都用 MD5 签名.
+//  respSignRequired: 回包是否必须有 sign 参数; 发放红包, 付款到银行卡等接口的回包有签名, 必须校验,
+//  查询红包, 企业付款等接口的回包没有签名, 这时候回包有 sign 参数才校验.
+func (clt *Client) postMMPayXML(url string, req map[string]string, respSignRequired bool) (resp map[string]string, err error) {
+	if _, ok := req["sign"]; !ok {
+		signType, ok := req["sign_type"]
+		if !ok {
+			signType = SignTypeMD5
+		}
+		var key string
+		if key, err = clt.signKey(); err != nil {
+			return
+		}
+		if req["sign"], err = SignWithType(req, key, signType); err != nil {
+			delete(req, "sign")
+			return
+		}
+	}
+	return clt.postXML(url, req, respSignRequired)
 }
@@ -0,0 +1,46 @@
+package pay
+
+import (
+	"testing"
+)
+
+func TestPostMMPayXMLResponseSignature(t *testing.T) {
+	stub := newStubAPI()
+	defer stub.Close()
+
+	// 回包都没有签名
+	stub.Handle("/mmpaymkttransfers/sendredpack", "", func(req map[string]string) map[string]string {
+		return map[string]string{"result_code": ResultCodeSuccess, "mch_billno": req["mch_billno"]}
+	})
+	stub.Handle("/mmpaymkttransfers/promotion/transfers", "", func(req map[string]string) map[string]string {
+		return map[string]string{"result_code": ResultCodeSuccess, "payment_no": "1000018301201505190181489473"}
+	})
+	// 回包有签名
+	stub.Handle("/mmpaymkttransfers/sendgroupredpack", signTestAPIKey, func(req map[string]string) map[string]string {
+		return map[string]string{"result_code": ResultCodeSuccess, "mch_billno": req["mch_billno"]}
+	})
+
+	clt := NewClientWithSignType(signTestAPIKey, SignTypeHMACSHA256, stub.HttpClient())
+
+	// 发放红包的回包必须有签名
+	if _, err := clt.SendRedPack(map[string]string{"mch_billno": "0010010404201411170000046545"}); err == nil {
+		t.Error("SendRedPack with unsigned response: want error")
+	}
+
+	req := map[string]string{"mch_billno": "0010010404201411170000046546"}
+	if _, err := clt.SendGroupRedPack(req); err != nil {
+		t.Errorf("SendGroupRedPack: %v", err)
+	}
+	if _, ok := req["sign_type"]; ok {
+		t.Errorf("SendGroupRedPack request should be signed with MD5 without sign_type: %v", req)
+	}
+
+	// 企业付款的回包没有签名
+	resp, err := clt.Transfers(map[string]string{"partner_trade_no": "10000098201411111234567890"})
+	if err != nil {
+		t.Fatalf("Transfers: %v", err)
+	}
+	if resp["payment_no"] != "1000018301201505190181489473" {
+		t.Errorf("Transfers: %v", resp)
+	}
+}
@@ -0,0 +1,88 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package pay
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+)
+
+// 获取RSA加密公钥API, 返回的 pub_key 为 PKCS#1 格式的 PEM 公钥, 见 ParseRSAPublicKey.
+//  NOTE: 请求需要双向证书; 该接口要求 sign_type 为 MD5.
+func (clt *Client) GetPublicKey(req map[string]string) (resp map[string]string, err error) {
+	if _, ok := req["sign_type"]; !ok {
+		req["sign_type"] = SignTypeMD5
+	}
+	return clt.postMMPayXML("https://fraud.mch.weixin.qq.com/risk/getpublickey", req, false)
+}
+
+// 企业付款到银行卡API.
+//  req 中的 enc_bank_no, enc_true_name 需要用 GetPublicKey 获取的公钥加密, 见 EncryptBankInfo.
+//  NOTE: 请求需要双向证书
+func (clt *Client) PayBank(req map[string]string) (resp map[string]string, err error) {
+	return clt.postMMPayXML("https://api.mch.weixin.qq.com/mmpaysptrans/pay_bank", req, true)
+}
+
+// 查询企业付款银行卡API.
+//  NOTE: 请求需要双向证书
+func (clt *Client) QueryBank(req map[string]string) (resp map[string]string, err error) {
+	return clt.postMMPayXML("https://api.mch.weixin.qq.com/mmpaysptrans/query_bank", req, false)
+}
+
+// 解析 GetPublicKey 返回的 pub_key.
+//  微信返回的是 PKCS#1 格式(BEGIN RSA PUBLIC KEY), 这里同时兼容 PKIX 格式(BEGIN PUBLIC KEY).
+func ParseRSAPublicKey(pubKeyPEM string) (pub *rsa.PublicKey, err error) {
+	block, _ := pem.Decode([]byte(pubKeyPEM))
+	if block == nil {
+		err = errors.New("invalid PEM public key")
+		return
+	}
+
+	if pub, err = x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return
+	}
+	pub, ok := key.(*rsa.PublicKey)
+	if !ok {
+		err = errors.New("the public key is not a RSA public key")
+		return
+	}
+	return
+}
+
+// 企业付款到银行卡的敏感信息加密, RSA/ECB/OAEPWITHSHA-1ANDMGF1PADDING, 结果为 base64 编码.
+func EncryptBankField(pub *rsa.PublicKey, plaintext string) (ciphertext string, err error) {
+	if pub == nil {
+		err = errors.New("nil rsa.PublicKey")
+		return
+	}
+	data, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, pub, []byte(plaintext), nil)
+	if err != nil {
+		return
+	}
+	ciphertext = base64.StdEncoding.EncodeToString(data)
+	return
+}
+
+// 加密收款方银行卡号和收款方用户名, 分别对应 PayBank 的 enc_bank_no 和 enc_true_name.
+func EncryptBankInfo(pub *rsa.PublicKey, bankNo, trueName string) (encBankNo, encTrueName string, err error) {
+	if encBankNo, err = EncryptBankField(pub, bankNo); err != nil {
+		return
+	}
+	if encTrueName, err = EncryptBankField(pub, trueName); err != nil {
+		encBankNo = ""
+		return
+	}
+	return
+}
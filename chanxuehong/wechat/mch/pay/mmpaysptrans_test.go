@@ -0,0 +1,81 @@
+package pay
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+)
+
+func TestEncryptBankInfo(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkixBytes, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pemKeys := map[string]string{
+		"PKCS#1": string(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&priv.PublicKey)})),
+		"PKIX":   string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkixBytes})),
+	}
+	for format, pemKey := range pemKeys {
+		pub, err := ParseRSAPublicKey(pemKey)
+		if err != nil {
+			t.Errorf("ParseRSAPublicKey(%s): %v", format, err)
+			continue
+		}
+
+		encBankNo, encTrueName, err := EncryptBankInfo(pub, "6225760008219524", "张三")
+		if err != nil {
+			t.Errorf("EncryptBankInfo(%s): %v", format, err)
+			continue
+		}
+		for ciphertext, want := range map[string]string{encBankNo: "6225760008219524", encTrueName: "张三"} {
+			data, err := base64.StdEncoding.DecodeString(ciphertext)
+			if err != nil {
+				t.Errorf("%s: %v", format, err)
+				continue
+			}
+			plaintext, err := rsa.DecryptOAEP(sha1.New(), nil, priv, data, nil)
+			if err != nil {
+				t.Errorf("%s DecryptOAEP: %v", format, err)
+				continue
+			}
+			if string(plaintext) != want {
+				t.Errorf("%s:\nhave %s\nwant %s\n", format, plaintext, want)
+			}
+		}
+	}
+
+	if _, err = ParseRSAPublicKey("not a pem"); err == nil {
+		t.Error("ParseRSAPublicKey with invalid PEM: want error")
+	}
+	if _, _, err = EncryptBankInfo(nil, "6225760008219524", "张三"); err == nil {
+		t.Error("EncryptBankInfo with nil key: want error")
+	}
+}
+
+// 仿真测试系统没有 fraud.mch.weixin.qq.com 的接口, 沙箱 Client 不能把请求发到正式环境
+func TestGetPublicKeySandbox(t *testing.T) {
+	stub := newStubAPI()
+	defer stub.Close()
+
+	stub.Handle("/sandboxnew/pay/getsignkey", signTestAPIKey, func(req map[string]string) map[string]string {
+		return map[string]string{"sandbox_signkey": "d41d8cd98f00b204e9800998ecf8427e"}
+	})
+	stub.Handle("/risk/getpublickey", "", func(req map[string]string) map[string]string {
+		t.Error("the request should not be sent")
+		return map[string]string{"result_code": ResultCodeSuccess}
+	})
+
+	clt := NewSandboxClient("10000100", signTestAPIKey, stub.HttpClient())
+	if _, err := clt.GetPublicKey(map[string]string{"mch_id": "10000100", "nonce_str": NewNonceStr()}); err == nil {
+		t.Error("GetPublicKey with a sandbox client: want error")
+	}
+}
@@ -45,4 +45,7 @@ type Request struct {
 
 	RawMsgXML []byte            // 消息的 XML 文本
 	Msg       map[string]string // 解析后的消息
+
+	// 退款结果通知并且 MessageServer 没有实现 RefundMessageServer 时才有, 为解密后的 req_info 的键值集合, 见 RefundMessageServer
+	ReqInfo map[string]string
 }
@@ -13,7 +13,7 @@ type MessageServer interface {
 	MessageHandler() MessageHandler // 获取 MessageHandler
 }
 
-var _ MessageServer = (*DefaultMessageServer)(nil)
+var _ RefundMessageServer = (*DefaultMessageServer)(nil)
 
 type DefaultMessageServer struct {
 	appId  string
@@ -21,6 +21,7 @@ type DefaultMessageServer struct {
 	apiKey string
 
 	messageHandler MessageHandler
+	refundHandler  RefundHandler
 }
 
 func NewDefaultMessageServer(appId, mchId, apiKey string, handler MessageHandler) *DefaultMessageServer {
@@ -48,3 +49,14 @@ func (srv *DefaultMessageServer) APIKey() string {
 func (srv *DefaultMessageServer) MessageHandler() MessageHandler {
 	return srv.messageHandler
 }
+
+// 获取 RefundHandler, 没有设置则返回 nil, 这时候退款结果通知直接回复 SUCCESS.
+func (srv *DefaultMessageServer) RefundHandler() RefundHandler {
+	return srv.refundHandler
+}
+
+// 设置退款结果通知的 RefundHandler.
+//  NOTE: 应该在处理消息之前设置, 不是并发安全的.
+func (srv *DefaultMessageServer) SetRefundHandler(handler RefundHandler) {
+	srv.refundHandler = handler
+}
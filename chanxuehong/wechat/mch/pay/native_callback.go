@@ -0,0 +1,155 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package pay
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/chanxuehong/util"
+)
+
+// 根据扫码原生支付模式1回调中的 product_id 和 openid 生成统一下单的请求参数.
+//  返回的 req 至少要包含 body, out_trade_no, total_fee, spbill_create_ip, notify_url;
+//  appid, mch_id, nonce_str, trade_type(NATIVE), product_id, openid 如果没有则自动补全.
+//  返回的 err != nil 时, 回复微信服务器 result_code=FAIL, err_code_des 为 err.Error(), 会展示给用户.
+type NativeOrderFunc func(productId, openId string) (req map[string]string, err error)
+
+// 统一下单出错(网络错误, 签名错误等)时回复给微信服务器的 err_code_des, 会展示给用户, 具体的错误见 NativeCallbackHandler.OnError.
+const NativeCallbackErrCodeDes = "系统繁忙, 请稍后再试"
+
+var _ http.Handler = (*NativeCallbackHandler)(nil)
+
+// 处理扫码原生支付模式1回调的 http.Handler, 见 NativeURL1.
+type NativeCallbackHandler struct {
+	appId                 string
+	mchId                 string
+	clt                   *Client
+	orderFunc             NativeOrderFunc
+	invalidRequestHandler InvalidRequestHandler
+
+	// 可选; 统一下单出错时的回调, 这时候回复给微信服务器的 err_code_des 为 NativeCallbackErrCodeDes.
+	//  NOTE: 应该在处理请求之前设置.
+	OnError func(productId, openId string, err error)
+}
+
+// 创建处理扫码原生支付模式1回调的 http.Handler, 见 NativeURL1.
+//  用户扫码后微信服务器回调该地址(公众平台设置的"原生支付回调URL"), Handler 校验 appid, mch_id 和签名后,
+//  调用 orderFunc 生成订单, 然后调用 Client.UnifiedOrder 统一下单, 最后把 prepay_id 签名后回复给微信服务器.
+//  校验和回复的签名密钥与 clt 一致, clt 是沙箱 Client 时为 sandbox_signkey.
+//  回调请求不应该有 return_code, 有 return_code 的请求和校验失败的请求都交给 invalidRequestHandler 处理.
+//  如果 invalidRequestHandler == nil 则默认用 DefaultInvalidRequestHandler.
+func NewNativeCallbackHandler(appId, mchId string, clt *Client, orderFunc NativeOrderFunc,
+	invalidRequestHandler InvalidRequestHandler) *NativeCallbackHandler {
+
+	if clt == nil {
+		panic("pay: nil Client")
+	}
+	if orderFunc == nil {
+		panic("pay: nil NativeOrderFunc")
+	}
+	if invalidRequestHandler == nil {
+		invalidRequestHandler = DefaultInvalidRequestHandler
+	}
+
+	return &NativeCallbackHandler{
+		appId:                 appId,
+		mchId:                 mchId,
+		clt:                   clt,
+		orderFunc:             orderFunc,
+		invalidRequestHandler: invalidRequestHandler,
+	}
+}
+
+func (handler *NativeCallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		handler.invalidRequestHandler.ServeInvalidRequest(w, r, errors.New("Request.Method: "+r.Method))
+		return
+	}
+	msg, err := util.ParseXMLToMap(r.Body)
+	if err != nil {
+		handler.invalidRequestHandler.ServeInvalidRequest(w, r, err)
+		return
+	}
+
+	// 回调请求没有 return_code, 有 return_code 的不是回调请求
+	if _, ok := msg["return_code"]; ok {
+		handler.invalidRequestHandler.ServeInvalidRequest(w, r, errors.New("unexpected return_code in native callback"))
+		return
+	}
+	if err = checkAppIdMchId(msg, handler.appId, handler.mchId); err != nil {
+		handler.invalidRequestHandler.ServeInvalidRequest(w, r, err)
+		return
+	}
+	key, err := handler.clt.signKey()
+	if err != nil {
+		handler.invalidRequestHandler.ServeInvalidRequest(w, r, err)
+		return
+	}
+	if err = CheckSignature(msg, key, msg["sign_type"]); err != nil {
+		handler.invalidRequestHandler.ServeInvalidRequest(w, r, err)
+		return
+	}
+
+	productId := msg["product_id"]
+	openId := msg["openid"]
+
+	req, err := handler.orderFunc(productId, openId)
+	if err != nil {
+		handler.reply(w, key, "", err.Error())
+		return
+	}
+	if req == nil {
+		req = make(map[string]string)
+	}
+	setDefault(req, "appid", handler.appId)
+	setDefault(req, "mch_id", handler.mchId)
+	setDefault(req, "nonce_str", NewNonceStr())
+	setDefault(req, "trade_type", TradeTypeNative)
+	setDefault(req, "product_id", productId)
+	setDefault(req, "openid", openId)
+
+	resp, err := handler.clt.UnifiedOrder(req)
+	if err == nil && resp["result_code"] != ResultCodeSuccess {
+		err = &ResultError{ErrCode: resp["err_code"], ErrCodeDes: resp["err_code_des"]}
+	}
+	if err != nil {
+		if handler.OnError != nil {
+			handler.OnError(productId, openId, err)
+		}
+		handler.reply(w, key, "", NativeCallbackErrCodeDes)
+		return
+	}
+	handler.reply(w, key, resp["prepay_id"], "")
+}
+
+// 回复微信服务器, errCodeDes 不为空时 result_code 为 FAIL.
+//  NOTE: 模式1的回复不支持 sign_type 参数, 只能用 MD5 签名.
+func (handler *NativeCallbackHandler) reply(w http.ResponseWriter, key, prepayId, errCodeDes string) {
+	m := make(map[string]string, 8)
+	m["return_code"] = ReturnCodeSuccess
+	m["appid"] = handler.appId
+	m["mch_id"] = handler.mchId
+	m["nonce_str"] = NewNonceStr()
+	if errCodeDes != "" {
+		m["result_code"] = ResultCodeFail
+		m["err_code_des"] = errCodeDes
+	} else {
+		m["result_code"] = ResultCodeSuccess
+		m["prepay_id"] = prepayId
+	}
+	m["sign"] = SignMD5(m, key)
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	util.FormatMapToXML(w, m)
+}
+
+// m[key] 不存在或者为空时设置为 value
+func setDefault(m map[string]string, key, value string) {
+	if m[key] == "" {
+		m[key] = value
+	}
+}
@@ -0,0 +1,184 @@
+package pay
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chanxuehong/util"
+)
+
+const (
+	nativeTestAppId = "wx2421b1c4370ec43b"
+	nativeTestMchId = "10000100"
+)
+
+// 模拟微信服务器的扫码回调, 返回回复的参数; 请求无效时返回 nil.
+func serveNativeCallback(t *testing.T, handler http.Handler, key string) map[string]string {
+	msg := map[string]string{
+		"appid":        nativeTestAppId,
+		"mch_id":       nativeTestMchId,
+		"openid":       "o8GeHuLAsgefS_80exEr1cTqekUs",
+		"is_subscribe": "Y",
+		"product_id":   "88888",
+		"nonce_str":    NewNonceStr(),
+	}
+	msg["sign"] = SignMD5(msg, key)
+
+	body := bytes.NewBuffer(nil)
+	util.FormatMapToXML(body, msg)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/native", body))
+	if w.Body.Len() == 0 {
+		return nil
+	}
+
+	reply, err := util.ParseXMLToMap(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = CheckSignature(reply, key, SignTypeMD5); err != nil {
+		t.Errorf("reply signature: %v", err)
+	}
+	return reply
+}
+
+func nativeTestOrderFunc(productId, openId string) (map[string]string, error) {
+	return map[string]string{
+		"body":             "native",
+		"out_trade_no":     "native" + productId,
+		"total_fee":        "1",
+		"spbill_create_ip": "127.0.0.1",
+		"notify_url":       "http://example.com/pay/notify",
+	}, nil
+}
+
+func TestNativeCallbackHandler(t *testing.T) {
+	stub := newStubAPI()
+	defer stub.Close()
+
+	var order map[string]string
+	stub.Handle("/pay/unifiedorder", signTestAPIKey, func(req map[string]string) map[string]string {
+		order = req
+		return map[string]string{"result_code": ResultCodeSuccess, "trade_type": TradeTypeNative, "prepay_id": "wx201410272009395522657a690389285100"}
+	})
+
+	clt := NewClient(signTestAPIKey, stub.HttpClient())
+	handler := NewNativeCallbackHandler(nativeTestAppId, nativeTestMchId, clt, nativeTestOrderFunc, nil)
+
+	reply := serveNativeCallback(t, handler, signTestAPIKey)
+	if reply["return_code"] != ReturnCodeSuccess || reply["result_code"] != ResultCodeSuccess ||
+		reply["prepay_id"] != "wx201410272009395522657a690389285100" {
+		t.Errorf("reply: %v", reply)
+	}
+	if order["trade_type"] != TradeTypeNative || order["product_id"] != "88888" ||
+		order["openid"] != "o8GeHuLAsgefS_80exEr1cTqekUs" || order["out_trade_no"] != "native88888" {
+		t.Errorf("unifiedorder request: %v", order)
+	}
+
+	if reply = serveNativeCallback(t, handler, "wrong key"); reply != nil {
+		t.Errorf("reply to a request with wrong signature: %v", reply)
+	}
+}
+
+func TestNativeCallbackHandlerError(t *testing.T) {
+	stub := newStubAPI()
+	defer stub.Close()
+
+	stub.Handle("/pay/unifiedorder", signTestAPIKey, func(req map[string]string) map[string]string {
+		return map[string]string{"result_code": ResultCodeFail, "err_code": "SYSTEMERROR", "err_code_des": "internal: db 10.0.0.1 timeout"}
+	})
+
+	clt := NewClient(signTestAPIKey, stub.HttpClient())
+	handler := NewNativeCallbackHandler(nativeTestAppId, nativeTestMchId, clt, nativeTestOrderFunc, nil)
+	var orderErr error
+	handler.OnError = func(productId, openId string, err error) {
+		orderErr = err
+	}
+	reply := serveNativeCallback(t, handler, signTestAPIKey)
+	if reply["result_code"] != ResultCodeFail || reply["err_code_des"] != NativeCallbackErrCodeDes {
+		t.Errorf("reply when UnifiedOrder failed: %v", reply)
+	}
+	if e, ok := orderErr.(*ResultError); !ok || e.ErrCode != "SYSTEMERROR" {
+		t.Errorf("OnError: %v", orderErr)
+	}
+
+	// orderFunc 的错误会展示给用户
+	handler = NewNativeCallbackHandler(nativeTestAppId, nativeTestMchId, clt, func(string, string) (map[string]string, error) {
+		return nil, errors.New("商品已下架")
+	}, nil)
+	reply = serveNativeCallback(t, handler, signTestAPIKey)
+	if reply["result_code"] != ResultCodeFail || reply["err_code_des"] != "商品已下架" {
+		t.Errorf("reply when orderFunc failed: %v", reply)
+	}
+}
+
+func TestNativeCallbackHandlerSandbox(t *testing.T) {
+	const sandboxSignKey = "d41d8cd98f00b204e9800998ecf8427e"
+
+	stub := newStubAPI()
+	defer stub.Close()
+
+	stub.Handle("/sandboxnew/pay/getsignkey", signTestAPIKey, func(req map[string]string) map[string]string {
+		return map[string]string{"sandbox_signkey": sandboxSignKey}
+	})
+	stub.Handle("/sandboxnew/pay/unifiedorder", sandboxSignKey, func(req map[string]string) map[string]string {
+		return map[string]string{"result_code": ResultCodeSuccess, "trade_type": TradeTypeNative, "prepay_id": "wx201411101639507cbf6ffd8b0779950874"}
+	})
+
+	clt := NewSandboxClient(nativeTestMchId, signTestAPIKey, stub.HttpClient())
+	var invalidErr error
+	handler := NewNativeCallbackHandler(nativeTestAppId, nativeTestMchId, clt, nativeTestOrderFunc,
+		InvalidRequestHandlerFunc(func(w http.ResponseWriter, r *http.Request, err error) {
+			invalidErr = err
+		}))
+
+	reply := serveNativeCallback(t, handler, sandboxSignKey)
+	if reply["result_code"] != ResultCodeSuccess || reply["prepay_id"] != "wx201411101639507cbf6ffd8b0779950874" {
+		t.Errorf("reply: %v", reply)
+	}
+
+	// 沙箱模式下用 API密钥 签名的请求是无效的
+	if reply = serveNativeCallback(t, handler, signTestAPIKey); reply != nil || invalidErr == nil {
+		t.Errorf("reply to a request signed with the API key: %v, %v", reply, invalidErr)
+	}
+	if invalidErr != nil && !strings.Contains(invalidErr.Error(), "sign") {
+		t.Errorf("invalid request error: %v", invalidErr)
+	}
+}
+
+// 有 return_code 的请求不是回调请求, 不管 return_code 是什么都要拒绝
+func TestNativeCallbackHandlerReturnCode(t *testing.T) {
+	var invalidErr error
+	handler := NewNativeCallbackHandler(nativeTestAppId, nativeTestMchId, NewClient(signTestAPIKey, nil),
+		func(string, string) (map[string]string, error) {
+			t.Error("orderFunc should not be called")
+			return nil, errors.New("unreachable")
+		},
+		InvalidRequestHandlerFunc(func(w http.ResponseWriter, r *http.Request, err error) {
+			invalidErr = err
+		}))
+
+	for _, returnCode := range []string{ReturnCodeFail, ReturnCodeSuccess} {
+		msg := map[string]string{
+			"return_code": returnCode,
+			"appid":       nativeTestAppId,
+			"mch_id":      nativeTestMchId,
+			"openid":      "o8GeHuLAsgefS_80exEr1cTqekUs",
+			"product_id":  "88888",
+			"nonce_str":   NewNonceStr(),
+		}
+		msg["sign"] = SignMD5(msg, signTestAPIKey)
+
+		invalidErr = nil
+		body := bytes.NewBuffer(nil)
+		util.FormatMapToXML(body, msg)
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/native", body))
+		if invalidErr == nil {
+			t.Errorf("return_code %s: want invalid request", returnCode)
+		}
+	}
+}
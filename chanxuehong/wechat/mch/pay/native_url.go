@@ -10,6 +10,7 @@ import (
 )
 
 // 扫码原生支付模式1的地址
+//  用户扫码后的回调用 NewNativeCallbackHandler 处理.
 func NativeURL1(appId, mchId, productId, timestamp, nonceStr, apiKey string) string {
 	m := make(map[string]string, 5)
 	m["appid"] = appId
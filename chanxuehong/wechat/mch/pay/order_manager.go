@@ -0,0 +1,359 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package pay
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// 交易状态, trade_state
+	TradeStateSuccess    = "SUCCESS"    // 支付成功
+	TradeStateRefund     = "REFUND"     // 转入退款
+	TradeStateNotPay     = "NOTPAY"     // 未支付
+	TradeStateClosed     = "CLOSED"     // 已关闭
+	TradeStateRevoked    = "REVOKED"    // 已撤销(刷卡支付)
+	TradeStateUserPaying = "USERPAYING" // 用户支付中
+	TradeStatePayError   = "PAYERROR"   // 支付失败(其他原因, 如银行返回失败)
+)
+
+// 交易状态允许的迁移, 空字符串表示 OrderManager 还没有记录该订单
+var tradeStateTransitions = map[string][]string{
+	"":                   {TradeStateNotPay, TradeStateUserPaying, TradeStateSuccess, TradeStateRefund, TradeStateClosed, TradeStateRevoked, TradeStatePayError},
+	TradeStateNotPay:     {TradeStateUserPaying, TradeStateSuccess, TradeStateRefund, TradeStateClosed, TradeStateRevoked, TradeStatePayError},
+	TradeStateUserPaying: {TradeStateSuccess, TradeStateRefund, TradeStateClosed, TradeStateRevoked, TradeStatePayError},
+	TradeStateSuccess:    {TradeStateRefund},
+}
+
+// 交易状态 from 是否可以迁移到 to.
+//  CLOSED, REVOKED, PAYERROR, REFUND 为终态, 不能再迁移;
+//  NOTPAY, USERPAYING 可以直接迁移到 REFUND, 因为支付成功后马上退款时可能查询不到 SUCCESS.
+func CanTransitTradeState(from, to string) bool {
+	for _, state := range tradeStateTransitions[from] {
+		if state == to {
+			return true
+		}
+	}
+	return false
+}
+
+// 订单的交易状态变化回调, resp 为引起变化的接口返回结果或者通知(可能为 nil).
+//  同一个订单的回调按照状态变化的顺序串行执行, 回调中不能调用会改变同一个订单状态的方法(Query, Close 等),
+//  但是可以调用 State 和 Remove.
+type OrderStateChangeFunc func(outTradeNo, oldState, newState string, resp map[string]string)
+
+// 刷卡支付(MicroPay)返回这些错误码时支付结果未知, 需要查询订单
+var microPayUnknownErrCodes = map[string]bool{
+	"USERPAYING":  true,
+	"SYSTEMERROR": true,
+	"BANKERROR":   true,
+}
+
+// 订单管理器, 在 Client 的基础上维护订单的交易状态:
+//  1. UnifiedOrder 下单后, 超过期限仍未支付则自动关闭订单;
+//  2. MicroPay 支付结果未知(USERPAYING 等)时按退避策略查询订单, 超时后自动撤销订单;
+//  3. 交易状态按照 CanTransitTradeState 迁移, 过期的查询结果不会覆盖新的状态, 每次变化都回调 OnStateChange.
+//  NOTE:
+//  1. 状态保存在内存中, 只适用于单进程; 导出的字段应该在使用前设置;
+//  2. 订单的记录不会自动删除(删除后过期的查询结果会被当作新的状态), 调用者需要在订单不再需要跟踪时调用 Remove,
+//     比如在 OnStateChange 中 newState 为终态(或者已经处理完成的 SUCCESS)的时候.
+type OrderManager struct {
+	clt   *Client
+	appId string
+	mchId string
+
+	QueryInterval    time.Duration        // MicroPay 结果未知时第一次查询前等待的时间, 默认 2s, 之后每次翻倍
+	MaxQueryInterval time.Duration        // 查询间隔的上限, 默认 10s
+	MicroPayTimeout  time.Duration        // MicroPay 等待用户支付的时间, 超时后撤销订单, 默认 60s
+	MaxReverseTimes  int                  // 撤销订单返回 recall=Y 时最多重试的次数, 默认 10
+	OnStateChange    OrderStateChangeFunc // 可选; 交易状态变化回调
+
+	mutex  sync.Mutex
+	states map[string]string      // out_trade_no --> trade_state
+	timers map[string]*time.Timer // out_trade_no --> 自动关闭订单的定时器
+	locks  map[string]*orderLock  // out_trade_no --> 订单状态变化的锁, 保证回调的顺序
+}
+
+type orderLock struct {
+	sync.Mutex
+	refs int // 正在使用或者等待该锁的数量
+}
+
+func NewOrderManager(clt *Client, appId, mchId string) *OrderManager {
+	if clt == nil {
+		panic("pay: nil Client")
+	}
+	return &OrderManager{
+		clt:              clt,
+		appId:            appId,
+		mchId:            mchId,
+		QueryInterval:    2 * time.Second,
+		MaxQueryInterval: 10 * time.Second,
+		MicroPayTimeout:  60 * time.Second,
+		MaxReverseTimes:  10,
+		states:           make(map[string]string),
+		timers:           make(map[string]*time.Timer),
+		locks:            make(map[string]*orderLock),
+	}
+}
+
+// 获取订单的交易状态, 没有记录则返回空字符串.
+func (m *OrderManager) State(outTradeNo string) string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.states[outTradeNo]
+}
+
+// 删除订单的记录, 同时取消自动关闭订单的定时器.
+//  删除后该订单的状态从空字符串重新开始迁移.
+func (m *OrderManager) Remove(outTradeNo string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if timer := m.timers[outTradeNo]; timer != nil {
+		timer.Stop()
+		delete(m.timers, outTradeNo)
+	}
+	delete(m.states, outTradeNo)
+}
+
+// 统一下单, 成功后订单状态为 NOTPAY.
+//  req 中 appid, mch_id, nonce_str 如果没有则自动补全;
+//  expire > 0 时, 超过 expire 仍未支付的订单会被自动关闭.
+func (m *OrderManager) UnifiedOrder(req map[string]string, expire time.Duration) (resp map[string]string, err error) {
+	outTradeNo := req["out_trade_no"]
+	if outTradeNo == "" {
+		err = errors.New("no out_trade_no parameter")
+		return
+	}
+	m.fillRequest(req)
+
+	if resp, err = m.clt.UnifiedOrder(req); err != nil {
+		return
+	}
+	if resp["result_code"] != ResultCodeSuccess {
+		return
+	}
+	m.setState(outTradeNo, TradeStateNotPay, resp)
+
+	if expire > 0 {
+		m.mutex.Lock()
+		if timer := m.timers[outTradeNo]; timer != nil {
+			timer.Stop()
+		}
+		var timer *time.Timer
+		timer = time.AfterFunc(expire, func() {
+			m.closeIfNotPaid(outTradeNo, &timer)
+		})
+		m.timers[outTradeNo] = timer
+		m.mutex.Unlock()
+	}
+	return
+}
+
+// 刷卡支付, 返回最终的交易状态.
+//  支付结果未知时会阻塞查询订单直到有确定的结果, 超过 MicroPayTimeout 则撤销订单, 返回 REVOKED.
+//  resp 为最后一次调用接口的返回结果.
+func (m *OrderManager) MicroPay(req map[string]string) (state string, resp map[string]string, err error) {
+	outTradeNo := req["out_trade_no"]
+	if outTradeNo == "" {
+		err = errors.New("no out_trade_no parameter")
+		return
+	}
+	m.fillRequest(req)
+
+	deadline := time.Now().Add(m.MicroPayTimeout)
+	resp, err = m.clt.MicroPay(req)
+	switch {
+	case err != nil:
+		if _, ok := err.(*Error); ok {
+			return // 协议错误, 支付没有发生
+		}
+		// 网络错误等, 支付结果未知
+	case resp["result_code"] == ResultCodeSuccess:
+		state = m.setState(outTradeNo, TradeStateSuccess, resp)
+		return
+	case !microPayUnknownErrCodes[resp["err_code"]]:
+		state = m.setState(outTradeNo, TradeStatePayError, resp)
+		return
+	}
+	m.setState(outTradeNo, TradeStateUserPaying, resp)
+
+	interval := m.QueryInterval
+	for time.Now().Add(interval).Before(deadline) {
+		time.Sleep(interval)
+		if interval *= 2; interval > m.MaxQueryInterval {
+			interval = m.MaxQueryInterval
+		}
+
+		var queryResp map[string]string
+		if state, queryResp, err = m.Query(outTradeNo); err != nil {
+			continue
+		}
+		resp = queryResp
+		if state != TradeStateUserPaying && state != TradeStateNotPay {
+			return
+		}
+	}
+
+	// 超时撤销
+	state, resp, err = m.Reverse(outTradeNo)
+	return
+}
+
+// 查询订单并更新交易状态, 返回更新后的交易状态.
+func (m *OrderManager) Query(outTradeNo string) (state string, resp map[string]string, err error) {
+	req := map[string]string{
+		"out_trade_no": outTradeNo,
+	}
+	m.fillRequest(req)
+
+	if resp, err = m.clt.OrderQuery(req); err != nil {
+		return
+	}
+	if resp["result_code"] != ResultCodeSuccess {
+		err = &ResultError{ErrCode: resp["err_code"], ErrCodeDes: resp["err_code_des"]}
+		return
+	}
+	state = m.setState(outTradeNo, resp["trade_state"], resp)
+	return
+}
+
+// 关闭订单, 成功后交易状态为 CLOSED.
+func (m *OrderManager) Close(outTradeNo string) (resp map[string]string, err error) {
+	req := map[string]string{
+		"out_trade_no": outTradeNo,
+	}
+	m.fillRequest(req)
+
+	if resp, err = m.clt.CloseOrder(req); err != nil {
+		return
+	}
+	if resp["result_code"] != ResultCodeSuccess {
+		err = &ResultError{ErrCode: resp["err_code"], ErrCodeDes: resp["err_code_des"]}
+		return
+	}
+	m.setState(outTradeNo, TradeStateClosed, resp)
+	return
+}
+
+// 撤销订单, 返回 recall=Y 时会重试, 最多 MaxReverseTimes 次; 成功后交易状态为 REVOKED.
+//  NOTE: 请求需要双向证书.
+func (m *OrderManager) Reverse(outTradeNo string) (state string, resp map[string]string, err error) {
+	for i := 0; ; i++ {
+		req := map[string]string{
+			"out_trade_no": outTradeNo,
+		}
+		m.fillRequest(req)
+
+		if resp, err = m.clt.Reverse(req); err != nil {
+			return
+		}
+		if resp["result_code"] == ResultCodeSuccess {
+			state = m.setState(outTradeNo, TradeStateRevoked, resp)
+			return
+		}
+		if resp["recall"] != "Y" || i+1 >= m.MaxReverseTimes {
+			err = &ResultError{ErrCode: resp["err_code"], ErrCodeDes: resp["err_code_des"]}
+			return
+		}
+		time.Sleep(m.QueryInterval)
+	}
+}
+
+// 处理支付结果通知, 更新交易状态, 返回更新后的交易状态; 一般在 NewPayResultHandler 的 fn 中调用.
+//  只有支付成功的通知才会迁移到 SUCCESS, 回调 OnStateChange 时 resp 为 nil;
+//  支付失败的通知不能确定交易状态(用户可能重新支付), 不改变交易状态, 需要的话用 Query 查询.
+func (m *OrderManager) HandlePayResult(result *PayResult) string {
+	if !result.IsSuccess() {
+		return m.State(result.OutTradeNo)
+	}
+	return m.setState(result.OutTradeNo, TradeStateSuccess, nil)
+}
+
+// 超过期限后, 订单仍未支付则关闭订单.
+//  timer 是触发这次调用的定时器, 在 m.mutex 保护下读取; 如果它已经不是 outTradeNo 注册的定时器
+//  (Timer.Stop 不能停止已经开始执行的函数, 这时候订单可能已经被 Remove 或者重新下单), 则什么都不做.
+func (m *OrderManager) closeIfNotPaid(outTradeNo string, timer **time.Timer) {
+	m.mutex.Lock()
+	if m.timers[outTradeNo] != *timer {
+		m.mutex.Unlock()
+		return
+	}
+	delete(m.timers, outTradeNo)
+	m.mutex.Unlock()
+
+	state, _, err := m.Query(outTradeNo)
+	if err != nil || state != TradeStateNotPay {
+		return
+	}
+	m.Close(outTradeNo)
+}
+
+func (m *OrderManager) fillRequest(req map[string]string) {
+	setDefault(req, "appid", m.appId)
+	setDefault(req, "mch_id", m.mchId)
+	setDefault(req, "nonce_str", NewNonceStr())
+}
+
+// 获取订单状态变化的锁.
+func (m *OrderManager) lockOrder(outTradeNo string) *orderLock {
+	m.mutex.Lock()
+	lock := m.locks[outTradeNo]
+	if lock == nil {
+		lock = new(orderLock)
+		m.locks[outTradeNo] = lock
+	}
+	lock.refs++
+	m.mutex.Unlock()
+
+	lock.Lock()
+	return lock
+}
+
+// 释放订单状态变化的锁, 没有人使用时删除.
+func (m *OrderManager) unlockOrder(outTradeNo string, lock *orderLock) {
+	lock.Unlock()
+
+	m.mutex.Lock()
+	if lock.refs--; lock.refs == 0 {
+		delete(m.locks, outTradeNo)
+	}
+	m.mutex.Unlock()
+}
+
+// 迁移交易状态, 返回迁移后的状态; 不允许的迁移被忽略, 返回当前的状态.
+//  持有订单的锁直到 OnStateChange 返回, 所以同一个订单的回调顺序和状态变化的顺序一致.
+func (m *OrderManager) setState(outTradeNo, state string, resp map[string]string) string {
+	lock := m.lockOrder(outTradeNo)
+	defer m.unlockOrder(outTradeNo, lock)
+
+	m.mutex.Lock()
+	oldState, ok := m.states[outTradeNo]
+	if oldState == state && ok {
+		m.mutex.Unlock()
+		return state
+	}
+	if !CanTransitTradeState(oldState, state) {
+		m.mutex.Unlock()
+		return oldState
+	}
+	m.states[outTradeNo] = state
+	if state != TradeStateNotPay && state != TradeStateUserPaying {
+		if timer := m.timers[outTradeNo]; timer != nil {
+			timer.Stop()
+			delete(m.timers, outTradeNo)
+		}
+	}
+	m.mutex.Unlock()
+
+	if m.OnStateChange != nil {
+		m.OnStateChange(outTradeNo, oldState, state, resp)
+	}
+	return state
+}
@@ -0,0 +1,218 @@
+package pay
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCanTransitTradeState(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{"", TradeStateNotPay, true},
+		{TradeStateNotPay, TradeStateUserPaying, true},
+		{TradeStateNotPay, TradeStateSuccess, true},
+		{TradeStateNotPay, TradeStateRefund, true},
+		{TradeStateUserPaying, TradeStateRefund, true},
+		{TradeStateUserPaying, TradeStateRevoked, true},
+		{TradeStateSuccess, TradeStateRefund, true},
+		{TradeStateUserPaying, TradeStateNotPay, false},
+		{TradeStateSuccess, TradeStateNotPay, false},
+		{TradeStateSuccess, TradeStateClosed, false},
+		{TradeStateClosed, TradeStateSuccess, false},
+		{TradeStateRevoked, TradeStateSuccess, false},
+		{TradeStatePayError, TradeStateSuccess, false},
+		{TradeStateRefund, TradeStateSuccess, false},
+	}
+	for _, tt := range tests {
+		if have := CanTransitTradeState(tt.from, tt.to); have != tt.want {
+			t.Errorf("CanTransitTradeState(%q, %q):\nhave %v\nwant %v\n", tt.from, tt.to, have, tt.want)
+		}
+	}
+}
+
+// 记录 OnStateChange 的回调
+type stateChangeRecorder struct {
+	mutex   sync.Mutex
+	changes []string
+}
+
+func (r *stateChangeRecorder) OnStateChange(outTradeNo, oldState, newState string, resp map[string]string) {
+	r.mutex.Lock()
+	r.changes = append(r.changes, oldState+"->"+newState)
+	r.mutex.Unlock()
+}
+
+func (r *stateChangeRecorder) String() string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return fmt.Sprint(r.changes)
+}
+
+func newTestOrderManager(stub *stubAPI) *OrderManager {
+	m := NewOrderManager(NewClient(signTestAPIKey, stub.HttpClient()), "wx2421b1c4370ec43b", "10000100")
+	m.QueryInterval = time.Millisecond
+	m.MaxQueryInterval = 4 * time.Millisecond
+	return m
+}
+
+func TestOrderManagerMicroPay(t *testing.T) {
+	stub := newStubAPI()
+	defer stub.Close()
+
+	queries := 0
+	stub.Handle("/pay/micropay", signTestAPIKey, func(req map[string]string) map[string]string {
+		return map[string]string{"result_code": ResultCodeFail, "err_code": "USERPAYING", "err_code_des": "需要用户输入支付密码"}
+	})
+	stub.Handle("/pay/orderquery", signTestAPIKey, func(req map[string]string) map[string]string {
+		queries++
+		if queries < 3 {
+			return map[string]string{"result_code": ResultCodeSuccess, "trade_state": TradeStateUserPaying}
+		}
+		return map[string]string{"result_code": ResultCodeSuccess, "trade_state": TradeStateSuccess, "transaction_id": "1217752501201407033233368018"}
+	})
+
+	m := newTestOrderManager(stub)
+	recorder := new(stateChangeRecorder)
+	m.OnStateChange = recorder.OnStateChange
+
+	state, resp, err := m.MicroPay(map[string]string{"out_trade_no": "1415757673", "auth_code": "120061098828009406"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != TradeStateSuccess || resp["transaction_id"] != "1217752501201407033233368018" {
+		t.Errorf("MicroPay: state %s, resp %v", state, resp)
+	}
+	if queries != 3 {
+		t.Errorf("queries: have %d, want 3", queries)
+	}
+	if have, want := recorder.String(), "[->USERPAYING USERPAYING->SUCCESS]"; have != want {
+		t.Errorf("OnStateChange:\nhave %s\nwant %s\n", have, want)
+	}
+}
+
+func TestOrderManagerMicroPayTimeout(t *testing.T) {
+	stub := newStubAPI()
+	defer stub.Close()
+
+	reversed := false
+	stub.Handle("/pay/micropay", signTestAPIKey, func(req map[string]string) map[string]string {
+		return map[string]string{"result_code": ResultCodeFail, "err_code": "SYSTEMERROR", "err_code_des": "系统超时"}
+	})
+	stub.Handle("/pay/orderquery", signTestAPIKey, func(req map[string]string) map[string]string {
+		return map[string]string{"result_code": ResultCodeSuccess, "trade_state": TradeStateUserPaying}
+	})
+	stub.Handle("/secapi/pay/reverse", signTestAPIKey, func(req map[string]string) map[string]string {
+		reversed = true
+		return map[string]string{"result_code": ResultCodeSuccess, "recall": "N"}
+	})
+
+	m := newTestOrderManager(stub)
+	m.MicroPayTimeout = 30 * time.Millisecond
+	state, _, err := m.MicroPay(map[string]string{"out_trade_no": "1415757674", "auth_code": "120061098828009406"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != TradeStateRevoked || !reversed {
+		t.Errorf("MicroPay: state %s, reversed %v", state, reversed)
+	}
+	if have := m.State("1415757674"); have != TradeStateRevoked {
+		t.Errorf("State: have %s, want %s", have, TradeStateRevoked)
+	}
+}
+
+func TestOrderManagerHandlePayResult(t *testing.T) {
+	stub := newStubAPI()
+	defer stub.Close()
+
+	stub.Handle("/pay/orderquery", signTestAPIKey, func(req map[string]string) map[string]string {
+		return map[string]string{"result_code": ResultCodeSuccess, "trade_state": TradeStateSuccess}
+	})
+
+	m := newTestOrderManager(stub)
+	m.setState("1409811653", TradeStateNotPay, nil)
+
+	// 支付失败的通知不改变交易状态, 之后查询到的 SUCCESS 仍然有效
+	if state := m.HandlePayResult(&PayResult{ReturnCode: ReturnCodeSuccess, ResultCode: ResultCodeFail, OutTradeNo: "1409811653"}); state != TradeStateNotPay {
+		t.Errorf("HandlePayResult(FAIL): have %s, want %s", state, TradeStateNotPay)
+	}
+	state, _, err := m.Query("1409811653")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != TradeStateSuccess {
+		t.Errorf("Query: have %s, want %s", state, TradeStateSuccess)
+	}
+	if state = m.HandlePayResult(&PayResult{ReturnCode: ReturnCodeSuccess, ResultCode: ResultCodeSuccess, OutTradeNo: "1409811654"}); state != TradeStateSuccess {
+		t.Errorf("HandlePayResult(SUCCESS): have %s, want %s", state, TradeStateSuccess)
+	}
+
+	m.Remove("1409811653")
+	if state = m.State("1409811653"); state != "" {
+		t.Errorf("State after Remove: %s", state)
+	}
+}
+
+func TestOrderManagerCallbackOrder(t *testing.T) {
+	m := NewOrderManager(NewClient(signTestAPIKey, nil), "wx2421b1c4370ec43b", "10000100")
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	recorder := new(stateChangeRecorder)
+	m.OnStateChange = func(outTradeNo, oldState, newState string, resp map[string]string) {
+		if newState == TradeStateNotPay {
+			close(entered)
+			<-release // 第一个回调还没有返回的时候状态又变化了
+		}
+		recorder.OnStateChange(outTradeNo, oldState, newState, resp)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		m.setState("1409811653", TradeStateNotPay, nil)
+	}()
+	<-entered
+	go func() {
+		defer wg.Done()
+		m.setState("1409811653", TradeStateSuccess, nil)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if have, want := recorder.String(), "[->NOTPAY NOTPAY->SUCCESS]"; have != want {
+		t.Errorf("OnStateChange:\nhave %s\nwant %s\n", have, want)
+	}
+	if len(m.locks) != 0 {
+		t.Errorf("locks leaked: %v", m.locks)
+	}
+}
+
+// 定时器已经被 Remove 或者替换后, 已经开始执行的 closeIfNotPaid 不能再查询订单
+func TestOrderManagerStaleCloseTimer(t *testing.T) {
+	stub := newStubAPI()
+	defer stub.Close()
+
+	var queries int
+	stub.Handle("/pay/orderquery", signTestAPIKey, func(req map[string]string) map[string]string {
+		queries++
+		return map[string]string{"result_code": ResultCodeSuccess, "trade_state": TradeStateNotPay}
+	})
+
+	m := newTestOrderManager(stub)
+	timer := time.NewTimer(time.Hour)
+	m.mutex.Lock()
+	m.timers["1409811653"] = timer
+	m.mutex.Unlock()
+
+	m.Remove("1409811653")
+	m.closeIfNotPaid("1409811653", &timer)
+	if queries != 0 || m.State("1409811653") != "" {
+		t.Errorf("queries: %d, state: %q", queries, m.State("1409811653"))
+	}
+}
@@ -0,0 +1,174 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package pay
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/chanxuehong/util/random"
+)
+
+// 公众号支付(JSAPI)的参数, 用于 WeixinJSBridge.invoke("getBrandWCPayRequest", ...).
+//  NOTE: JSSDK 的 chooseWXPay 参数名为 timestamp(全小写), 值同 TimeStamp.
+type JSAPIPayParameters struct {
+	AppId     string `json:"appId"`
+	TimeStamp string `json:"timeStamp"`
+	NonceStr  string `json:"nonceStr"`
+	Package   string `json:"package"`  // prepay_id=xxx
+	SignType  string `json:"signType"` // SignTypeMD5 或 SignTypeHMACSHA256
+	PaySign   string `json:"paySign"`
+}
+
+// APP支付的参数, 用于 APP 端 SDK 调起支付.
+type APPPayParameters struct {
+	AppId     string `json:"appid"`
+	PartnerId string `json:"partnerid"` // 商户号
+	PrepayId  string `json:"prepayid"`
+	Package   string `json:"package"` // 固定值 Sign=WXPay
+	NonceStr  string `json:"noncestr"`
+	TimeStamp string `json:"timestamp"`
+	Sign      string `json:"sign"`
+}
+
+// 根据统一下单(trade_type=JSAPI)的返回结果生成公众号支付的参数.
+//  resp:     UnifiedOrder 的返回结果
+//  apiKey:   API密钥
+//  signType: 签名类型, 必须与统一下单时的一致, 为空时默认为 SignTypeMD5
+func NewJSAPIPayParameters(resp map[string]string, apiKey, signType string) (para *JSAPIPayParameters, err error) {
+	prepayId, err := getPrepayId(resp, TradeTypeJSAPI)
+	if err != nil {
+		return
+	}
+	if signType == "" {
+		signType = SignTypeMD5
+	}
+
+	para = &JSAPIPayParameters{
+		AppId:     resp["appid"],
+		TimeStamp: strconv.FormatInt(time.Now().Unix(), 10),
+		NonceStr:  NewNonceStr(),
+		Package:   "prepay_id=" + prepayId,
+		SignType:  signType,
+	}
+
+	m := make(map[string]string, 5)
+	m["appId"] = para.AppId
+	m["timeStamp"] = para.TimeStamp
+	m["nonceStr"] = para.NonceStr
+	m["package"] = para.Package
+	m["signType"] = para.SignType
+
+	if para.PaySign, err = SignWithType(m, apiKey, signType); err != nil {
+		para = nil
+		return
+	}
+	return
+}
+
+// 根据统一下单(trade_type=APP)的返回结果生成APP支付的参数.
+//  resp:     UnifiedOrder 的返回结果
+//  apiKey:   API密钥
+//  signType: 签名类型, 必须与统一下单时的一致, 为空时默认为 SignTypeMD5
+func NewAPPPayParameters(resp map[string]string, apiKey, signType string) (para *APPPayParameters, err error) {
+	prepayId, err := getPrepayId(resp, TradeTypeAPP)
+	if err != nil {
+		return
+	}
+
+	para = &APPPayParameters{
+		AppId:     resp["appid"],
+		PartnerId: resp["mch_id"],
+		PrepayId:  prepayId,
+		Package:   "Sign=WXPay",
+		NonceStr:  NewNonceStr(),
+		TimeStamp: strconv.FormatInt(time.Now().Unix(), 10),
+	}
+
+	m := make(map[string]string, 6)
+	m["appid"] = para.AppId
+	m["partnerid"] = para.PartnerId
+	m["prepayid"] = para.PrepayId
+	m["package"] = para.Package
+	m["noncestr"] = para.NonceStr
+	m["timestamp"] = para.TimeStamp
+
+	if para.Sign, err = SignWithType(m, apiKey, signType); err != nil {
+		para = nil
+		return
+	}
+	return
+}
+
+// 根据统一下单(trade_type=MWEB)的返回结果生成H5支付的跳转地址.
+//  redirectURL: 可选; 支付完成后跳转的页面, 需要与 H5 支付的授权域名一致, 为空则不添加 redirect_url 参数
+func NewMWEBURL(resp map[string]string, redirectURL string) (mwebURL string, err error) {
+	if err = checkTradeType(resp, TradeTypeMWEB); err != nil {
+		return
+	}
+	mwebURL = resp["mweb_url"]
+	if mwebURL == "" {
+		err = errors.New("no mweb_url parameter")
+		return
+	}
+	if redirectURL == "" {
+		return
+	}
+
+	u, err := url.Parse(mwebURL)
+	if err != nil {
+		mwebURL = ""
+		return
+	}
+	query := u.Query()
+	query.Set("redirect_url", redirectURL)
+	u.RawQuery = query.Encode()
+	mwebURL = u.String()
+	return
+}
+
+// 根据统一下单(trade_type=NATIVE)的返回结果获取二维码链接 code_url, 见 NativeURL2.
+func NewNativeCodeURL(resp map[string]string) (codeURL string, err error) {
+	if err = checkTradeType(resp, TradeTypeNative); err != nil {
+		return
+	}
+	codeURL = resp["code_url"]
+	if codeURL == "" {
+		err = errors.New("no code_url parameter")
+		return
+	}
+	return
+}
+
+// 检查统一下单返回结果的 trade_type, 没有 trade_type 则不检查.
+func checkTradeType(resp map[string]string, tradeType string) error {
+	if have, ok := resp["trade_type"]; ok && have != tradeType {
+		return fmt.Errorf("the trade_type mismatch, have: %s, want: %s", have, tradeType)
+	}
+	return nil
+}
+
+func getPrepayId(resp map[string]string, tradeType string) (prepayId string, err error) {
+	if err = checkTradeType(resp, tradeType); err != nil {
+		return
+	}
+	prepayId = resp["prepay_id"]
+	if prepayId == "" {
+		err = errors.New("no prepay_id parameter")
+		return
+	}
+	return
+}
+
+// 生成 32 字节的随机字符串, 可以用作 nonce_str.
+func NewNonceStr() string {
+	nonce := random.NewRandom()
+	return hex.EncodeToString(nonce[:])
+}
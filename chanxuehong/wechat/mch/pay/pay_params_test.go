@@ -0,0 +1,138 @@
+package pay
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestNewJSAPIPayParameters(t *testing.T) {
+	resp := map[string]string{
+		"appid":      "wx2421b1c4370ec43b",
+		"trade_type": TradeTypeJSAPI,
+		"prepay_id":  "wx201410272009395522657a690389285100",
+	}
+	for _, signType := range []string{"", SignTypeMD5, SignTypeHMACSHA256} {
+		para, err := NewJSAPIPayParameters(resp, signTestAPIKey, signType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if para.AppId != resp["appid"] || para.Package != "prepay_id="+resp["prepay_id"] {
+			t.Errorf("NewJSAPIPayParameters(%q): %+v", signType, para)
+		}
+		m := map[string]string{
+			"appId":     para.AppId,
+			"timeStamp": para.TimeStamp,
+			"nonceStr":  para.NonceStr,
+			"package":   para.Package,
+			"signType":  para.SignType,
+			"sign":      para.PaySign,
+		}
+		if err = CheckSignature(m, signTestAPIKey, para.SignType); err != nil {
+			t.Errorf("NewJSAPIPayParameters(%q) PaySign: %v", signType, err)
+		}
+	}
+
+	if _, err := NewJSAPIPayParameters(resp, signTestAPIKey, "RSA"); err == nil {
+		t.Error("NewJSAPIPayParameters with unsupported signType: want error")
+	}
+	resp["trade_type"] = TradeTypeNative
+	if _, err := NewJSAPIPayParameters(resp, signTestAPIKey, ""); err == nil {
+		t.Error("NewJSAPIPayParameters with trade_type NATIVE: want error")
+	}
+}
+
+func TestNewAPPPayParameters(t *testing.T) {
+	resp := map[string]string{
+		"appid":      "wx2421b1c4370ec43b",
+		"mch_id":     "10000100",
+		"trade_type": TradeTypeAPP,
+		"prepay_id":  "wx201410272009395522657a690389285100",
+	}
+	para, err := NewAPPPayParameters(resp, signTestAPIKey, SignTypeHMACSHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if para.PartnerId != resp["mch_id"] || para.PrepayId != resp["prepay_id"] || para.Package != "Sign=WXPay" {
+		t.Errorf("NewAPPPayParameters: %+v", para)
+	}
+	m := map[string]string{
+		"appid":     para.AppId,
+		"partnerid": para.PartnerId,
+		"prepayid":  para.PrepayId,
+		"package":   para.Package,
+		"noncestr":  para.NonceStr,
+		"timestamp": para.TimeStamp,
+		"sign":      para.Sign,
+	}
+	if err = CheckSignature(m, signTestAPIKey, SignTypeHMACSHA256); err != nil {
+		t.Errorf("NewAPPPayParameters Sign: %v", err)
+	}
+
+	delete(resp, "prepay_id")
+	if _, err = NewAPPPayParameters(resp, signTestAPIKey, ""); err == nil {
+		t.Error("NewAPPPayParameters without prepay_id: want error")
+	}
+}
+
+func TestNewMWEBURL(t *testing.T) {
+	resp := map[string]string{
+		"trade_type": TradeTypeMWEB,
+		"mweb_url":   "https://wx.tenpay.com/cgi-bin/mmpayweb-bin/checkmweb?prepay_id=wx2016121516420242444321ca0631331346&package=1405458241",
+	}
+
+	mwebURL, err := NewMWEBURL(resp, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mwebURL != resp["mweb_url"] {
+		t.Errorf("NewMWEBURL without redirectURL:\nhave %s\nwant %s\n", mwebURL, resp["mweb_url"])
+	}
+
+	redirectURL := "https://www.example.com/pay/done?order=1409811653&from=h5"
+	if mwebURL, err = NewMWEBURL(resp, redirectURL); err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(mwebURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	if have := query.Get("redirect_url"); have != redirectURL {
+		t.Errorf("redirect_url:\nhave %s\nwant %s\n", have, redirectURL)
+	}
+	if query.Get("prepay_id") != "wx2016121516420242444321ca0631331346" || query.Get("package") != "1405458241" {
+		t.Errorf("NewMWEBURL lost the original parameters: %s", mwebURL)
+	}
+	if u.Host != "wx.tenpay.com" || u.Path != "/cgi-bin/mmpayweb-bin/checkmweb" {
+		t.Errorf("NewMWEBURL changed the url: %s", mwebURL)
+	}
+
+	// 已经有 redirect_url 参数的时候替换而不是重复添加
+	resp["mweb_url"] = mwebURL
+	if mwebURL, err = NewMWEBURL(resp, "https://www.example.com/"); err != nil {
+		t.Fatal(err)
+	}
+	u, _ = url.Parse(mwebURL)
+	if have := u.Query()["redirect_url"]; len(have) != 1 || have[0] != "https://www.example.com/" {
+		t.Errorf("redirect_url: have %v, want [https://www.example.com/]", have)
+	}
+}
+
+func TestNewNativeCodeURL(t *testing.T) {
+	resp := map[string]string{
+		"trade_type": TradeTypeNative,
+		"code_url":   "weixin://wxpay/bizpayurl?pr=kRMHhQc",
+	}
+	codeURL, err := NewNativeCodeURL(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if codeURL != resp["code_url"] {
+		t.Errorf("NewNativeCodeURL:\nhave %s\nwant %s\n", codeURL, resp["code_url"])
+	}
+
+	resp["trade_type"] = TradeTypeJSAPI
+	if _, err = NewNativeCodeURL(resp); err == nil {
+		t.Error("NewNativeCodeURL with trade_type JSAPI: want error")
+	}
+}
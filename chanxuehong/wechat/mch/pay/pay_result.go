@@ -0,0 +1,125 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package pay
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// 代金券或立减优惠
+type Coupon struct {
+	Type string // 代金券类型, CASH: 充值代金券, NO_CASH: 非充值代金券
+	Id   string // 代金券ID
+	Fee  int64  // 单个代金券支付金额, 单位为分
+}
+
+// 支付结果, 对应支付结果通知(或者订单查询)的参数集合
+type PayResult struct {
+	ReturnCode string // SUCCESS/FAIL, 此字段是通信标识, 非交易标识
+	ReturnMsg  string
+	ResultCode string // SUCCESS/FAIL, 业务结果
+	ErrCode    string
+	ErrCodeDes string
+
+	AppId       string // 公众账号ID
+	MchId       string // 商户号
+	DeviceInfo  string // 设备号
+	OpenId      string // 用户标识
+	IsSubscribe bool   // 用户是否关注公众账号
+	TradeType   string // 交易类型, JSAPI, NATIVE, APP
+	BankType    string // 付款银行
+
+	TotalFee           int64  // 订单总金额, 单位为分
+	SettlementTotalFee int64  // 应结订单金额, 当订单使用了免充值型优惠券后返回该参数
+	FeeType            string // 货币种类
+	CashFee            int64  // 现金支付金额, 单位为分
+	CashFeeType        string // 现金支付货币类型
+	CouponFee          int64  // 总代金券金额, 单位为分
+	CouponCount        int    // 代金券使用数量
+	Coupons            []Coupon
+
+	TransactionId string    // 微信支付订单号
+	OutTradeNo    string    // 商户订单号
+	Attach        string    // 商家数据包
+	TimeEnd       time.Time // 支付完成时间
+}
+
+// 是否支付成功, 即 return_code 和 result_code 都为 SUCCESS.
+func (result *PayResult) IsSuccess() bool {
+	return result.ReturnCode == ReturnCodeSuccess && result.ResultCode == ResultCodeSuccess
+}
+
+// 解析支付结果通知的参数集合.
+//  NOTE: 不校验签名, return_code 为 SUCCESS 时 ServeHTTP 已经校验过了; 否则消息没有签名, 除了 return_code 和 return_msg 都不能信任.
+func ParsePayResult(msg map[string]string) (result *PayResult, err error) {
+	result = &PayResult{
+		ReturnCode: msg["return_code"],
+		ReturnMsg:  msg["return_msg"],
+		ResultCode: msg["result_code"],
+		ErrCode:    msg["err_code"],
+		ErrCodeDes: msg["err_code_des"],
+
+		AppId:       msg["appid"],
+		MchId:       msg["mch_id"],
+		DeviceInfo:  msg["device_info"],
+		OpenId:      msg["openid"],
+		IsSubscribe: msg["is_subscribe"] == "Y",
+		TradeType:   msg["trade_type"],
+		BankType:    msg["bank_type"],
+
+		FeeType:     msg["fee_type"],
+		CashFeeType: msg["cash_fee_type"],
+
+		TransactionId: msg["transaction_id"],
+		OutTradeNo:    msg["out_trade_no"],
+		Attach:        msg["attach"],
+	}
+
+	if result.TotalFee, err = parseInt64(msg, "total_fee"); err != nil {
+		return nil, err
+	}
+	if result.SettlementTotalFee, err = parseInt64(msg, "settlement_total_fee"); err != nil {
+		return nil, err
+	}
+	if result.CashFee, err = parseInt64(msg, "cash_fee"); err != nil {
+		return nil, err
+	}
+	if result.CouponFee, err = parseInt64(msg, "coupon_fee"); err != nil {
+		return nil, err
+	}
+	if result.TimeEnd, err = parseTime(msg, "time_end", "20060102150405"); err != nil {
+		return nil, err
+	}
+
+	couponCount, err := parseInt64(msg, "coupon_count")
+	if err != nil {
+		return nil, err
+	}
+	if couponCount < 0 {
+		return nil, fmt.Errorf("invalid coupon_count: %d", couponCount)
+	}
+	result.CouponCount = int(couponCount)
+	if couponCount > 0 {
+		result.Coupons = make([]Coupon, couponCount)
+		for i := range result.Coupons {
+			n := strconv.Itoa(i)
+			coupon := &result.Coupons[i]
+			coupon.Type = msg["coupon_type_"+n]
+			coupon.Id = msg["coupon_id_"+n]
+			if coupon.Fee, err = parseInt64(msg, "coupon_fee_"+n); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return
+}
+
+// 解析支付结果通知, 见 ParsePayResult.
+func (r *Request) PayResult() (*PayResult, error) {
+	return ParsePayResult(r.Msg)
+}
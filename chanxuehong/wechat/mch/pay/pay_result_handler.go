@@ -0,0 +1,135 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package pay
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+var ErrPayResultProcessing = errors.New("the pay result is being processed")
+
+// 支付结果通知的幂等存储接口, 保证同一笔交易只被成功处理一次.
+//  微信服务器会重复通知同一笔交易, 多个进程部署时应该用共享的存储(数据库, redis 等)实现.
+type PayResultStore interface {
+	// 开始处理 key 对应的交易.
+	//  如果已经处理完成返回 done == true;
+	//  如果正在被其他请求处理应该返回 ErrPayResultProcessing;
+	//  否则标记为正在处理, 返回 done == false, err == nil.
+	Begin(key string) (done bool, err error)
+
+	// 处理成功, 标记为已经完成.
+	Done(key string) error
+
+	// 处理失败, 清除正在处理的标记, 以便微信服务器重新通知时可以再次处理.
+	Abort(key string) error
+}
+
+var _ PayResultStore = (*MemoryPayResultStore)(nil)
+
+// 基于内存的 PayResultStore, 一般用于测试或者单进程的应用.
+type MemoryPayResultStore struct {
+	mutex sync.Mutex
+	state map[string]bool // key --> done
+}
+
+func NewMemoryPayResultStore() *MemoryPayResultStore {
+	return &MemoryPayResultStore{
+		state: make(map[string]bool),
+	}
+}
+
+func (store *MemoryPayResultStore) Begin(key string) (done bool, err error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	done, ok := store.state[key]
+	if ok {
+		if done {
+			return
+		}
+		err = ErrPayResultProcessing
+		return
+	}
+	store.state[key] = false
+	return
+}
+
+func (store *MemoryPayResultStore) Done(key string) error {
+	store.mutex.Lock()
+	store.state[key] = true
+	store.mutex.Unlock()
+	return nil
+}
+
+func (store *MemoryPayResultStore) Abort(key string) error {
+	store.mutex.Lock()
+	if done := store.state[key]; !done {
+		delete(store.state, key)
+	}
+	store.mutex.Unlock()
+	return nil
+}
+
+// 创建一个处理支付结果通知的 MessageHandler, 通过 store 保证同一笔交易 fn 只被成功执行一次.
+//  交易以 transaction_id 标识, 没有 transaction_id 时以 out_trade_no 标识;
+//  fn 返回 nil 时回复 SUCCESS, 否则回复 FAIL, 微信服务器会稍后重新通知;
+//  已经处理过的交易直接回复 SUCCESS, 不再调用 fn.
+//  return_code 不为 SUCCESS 的通知(没有签名, 不能信任)和退款结果通知(Request.ReqInfo != nil)直接回复 SUCCESS, 不调用 fn.
+//  NOTE: fn 成功后 store.Done 失败也回复 SUCCESS, 因为交易已经处理过了, 不能让 fn 被再次执行,
+//  这时候 store 中的 key 可能一直是正在处理的状态, 需要 store 的实现自己清理(比如设置过期时间).
+func NewPayResultHandler(store PayResultStore, fn func(*PayResult) error) MessageHandler {
+	if store == nil {
+		panic("pay: nil PayResultStore")
+	}
+	if fn == nil {
+		panic("pay: nil fn")
+	}
+
+	return MessageHandlerFunc(func(w http.ResponseWriter, r *Request) {
+		if r.ReqInfo != nil {
+			ReplySuccess(w)
+			return
+		}
+		result, err := r.PayResult()
+		if err != nil {
+			ReplyFail(w, err.Error())
+			return
+		}
+		if result.ReturnCode != ReturnCodeSuccess {
+			ReplySuccess(w)
+			return
+		}
+
+		key := result.TransactionId
+		if key == "" {
+			key = result.OutTradeNo
+		}
+		if key == "" {
+			ReplyFail(w, "no transaction_id and out_trade_no parameter")
+			return
+		}
+
+		done, err := store.Begin(key)
+		if err != nil {
+			ReplyFail(w, err.Error())
+			return
+		}
+		if done {
+			ReplySuccess(w)
+			return
+		}
+
+		if err = fn(result); err != nil {
+			store.Abort(key)
+			ReplyFail(w, err.Error())
+			return
+		}
+		store.Done(key)
+		ReplySuccess(w)
+	})
+}
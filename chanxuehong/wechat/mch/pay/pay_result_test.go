@@ -0,0 +1,143 @@
+package pay
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chanxuehong/util"
+)
+
+var payResultTestMsg = map[string]string{
+	"return_code":    "SUCCESS",
+	"result_code":    "SUCCESS",
+	"appid":          "wx2421b1c4370ec43b",
+	"mch_id":         "10000100",
+	"openid":         "oUpF8uMEb4qRXf22hE3X68TekukE",
+	"is_subscribe":   "Y",
+	"trade_type":     "JSAPI",
+	"bank_type":      "CFT",
+	"total_fee":      "100",
+	"cash_fee":       "80",
+	"coupon_fee":     "20",
+	"coupon_count":   "2",
+	"coupon_type_0":  "CASH",
+	"coupon_id_0":    "10000",
+	"coupon_fee_0":   "15",
+	"coupon_type_1":  "NO_CASH",
+	"coupon_id_1":    "10001",
+	"coupon_fee_1":   "5",
+	"transaction_id": "1004400740201409030005092168",
+	"out_trade_no":   "1409811653",
+	"time_end":       "20140903131540",
+}
+
+func TestParsePayResult(t *testing.T) {
+	result, err := ParsePayResult(payResultTestMsg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsSuccess() || !result.IsSubscribe || result.TotalFee != 100 || result.CashFee != 80 || result.CouponFee != 20 {
+		t.Errorf("ParsePayResult: %+v", result)
+	}
+	wantCoupons := []Coupon{{"CASH", "10000", 15}, {"NO_CASH", "10001", 5}}
+	if len(result.Coupons) != len(wantCoupons) || result.CouponCount != len(wantCoupons) {
+		t.Fatalf("Coupons:\nhave %+v\nwant %+v\n", result.Coupons, wantCoupons)
+	}
+	for i := range wantCoupons {
+		if result.Coupons[i] != wantCoupons[i] {
+			t.Errorf("Coupons[%d]:\nhave %+v\nwant %+v\n", i, result.Coupons[i], wantCoupons[i])
+		}
+	}
+	if want := time.Date(2014, 9, 3, 13, 15, 40, 0, util.BeijingLocation); !result.TimeEnd.Equal(want) {
+		t.Errorf("TimeEnd:\nhave %v\nwant %v\n", result.TimeEnd, want)
+	}
+}
+
+func TestPayResultHandler(t *testing.T) {
+	var calls int
+	fail := true
+	handler := NewPayResultHandler(NewMemoryPayResultStore(), func(*PayResult) error {
+		calls++
+		if fail {
+			return errors.New("db down")
+		}
+		return nil
+	})
+
+	serve := func() string {
+		w := httptest.NewRecorder()
+		handler.ServeMessage(w, &Request{Msg: payResultTestMsg})
+		return w.Body.String()
+	}
+
+	if reply := serve(); !strings.Contains(reply, "<return_code>FAIL</return_code>") {
+		t.Errorf("first reply: %s", reply)
+	}
+	fail = false
+	if reply := serve(); !strings.Contains(reply, "<return_code>SUCCESS</return_code>") {
+		t.Errorf("second reply: %s", reply)
+	}
+	if reply := serve(); !strings.Contains(reply, "<return_code>SUCCESS</return_code>") {
+		t.Errorf("third reply: %s", reply)
+	}
+	if calls != 2 {
+		t.Errorf("calls: have %d, want 2", calls)
+	}
+}
+
+// Done 总是失败的 PayResultStore
+type doneFailStore struct {
+	*MemoryPayResultStore
+}
+
+func (doneFailStore) Done(key string) error {
+	return errors.New("db down")
+}
+
+func TestPayResultHandlerDoneFailed(t *testing.T) {
+	var calls int
+	handler := NewPayResultHandler(doneFailStore{NewMemoryPayResultStore()}, func(*PayResult) error {
+		calls++
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeMessage(w, &Request{Msg: payResultTestMsg})
+	if reply := w.Body.String(); !strings.Contains(reply, "<return_code>SUCCESS</return_code>") {
+		t.Errorf("reply: %s", reply)
+	}
+	if calls != 1 {
+		t.Errorf("calls: have %d, want 1", calls)
+	}
+}
+
+func TestPayResultHandlerSkip(t *testing.T) {
+	store := NewMemoryPayResultStore()
+	handler := NewPayResultHandler(store, func(*PayResult) error {
+		t.Error("fn should not be called")
+		return nil
+	})
+
+	failMsg := make(map[string]string, len(payResultTestMsg))
+	for k, v := range payResultTestMsg {
+		failMsg[k] = v
+	}
+	failMsg["return_code"] = ReturnCodeFail
+
+	for _, r := range []*Request{
+		{Msg: failMsg},
+		{Msg: payResultTestMsg, ReqInfo: map[string]string{"out_refund_no": "1409811653R"}},
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeMessage(w, r)
+		if reply := w.Body.String(); !strings.Contains(reply, "<return_code>SUCCESS</return_code>") {
+			t.Errorf("reply: %s", reply)
+		}
+	}
+	if len(store.state) != 0 {
+		t.Errorf("store: %v", store.state)
+	}
+}
@@ -0,0 +1,236 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package pay
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chanxuehong/util"
+)
+
+const (
+	// 退款状态
+	RefundStatusSuccess     = "SUCCESS"     // 退款成功
+	RefundStatusProcessing  = "PROCESSING"  // 退款处理中
+	RefundStatusChange      = "CHANGE"      // 退款异常
+	RefundStatusRefundClose = "REFUNDCLOSE" // 退款关闭
+)
+
+// 退款结果通知处理接口.
+//  返回 nil 时回复微信服务器 SUCCESS, 否则回复 FAIL, return_msg 为 err.Error().
+type RefundHandler interface {
+	ServeRefund(r *RefundRequest) error
+}
+
+type RefundHandlerFunc func(*RefundRequest) error
+
+func (fn RefundHandlerFunc) ServeRefund(r *RefundRequest) error {
+	return fn(r)
+}
+
+// 如果 MessageServer 同时实现了 RefundMessageServer, 则退款结果通知交给 RefundHandler 处理,
+// RefundHandler() == nil 时直接回复微信服务器 SUCCESS(退款结果可以通过查询退款接口获取);
+// 否则交给 MessageHandler 处理(见 Request.ReqInfo).
+type RefundMessageServer interface {
+	MessageServer
+	RefundHandler() RefundHandler // 获取 RefundHandler
+}
+
+// 退款结果通知请求信息
+type RefundRequest struct {
+	HttpRequest *http.Request // 可以为 nil, 因为某些 http 框架没有提供此参数
+
+	RawMsgXML []byte            // 消息的 XML 文本
+	Msg       map[string]string // 解析后的消息, 其中 req_info 为加密的原文
+
+	ReqInfoXML []byte            // 解密后的 req_info 的 XML 文本
+	ReqInfo    map[string]string // 解密后的 req_info 解析后的键值集合
+	Result     *RefundResult     // 解密后的 req_info 的结构化结果
+}
+
+// 退款结果, 对应退款结果通知中解密后的 req_info
+type RefundResult struct {
+	TransactionId       string    // 微信订单号
+	OutTradeNo          string    // 商户订单号
+	RefundId            string    // 微信退款单号
+	OutRefundNo         string    // 商户退款单号
+	TotalFee            int64     // 订单金额, 单位为分
+	SettlementTotalFee  int64     // 应结订单金额, 当该订单有使用非充值券时返回此字段
+	RefundFee           int64     // 申请退款金额, 单位为分
+	SettlementRefundFee int64     // 退款金额, 退款金额=申请退款金额-非充值代金券退款金额
+	RefundStatus        string    // 退款状态, RefundStatusXXX
+	SuccessTime         time.Time // 退款成功时间, 退款状态为 SUCCESS 时才有, 否则为零值
+	RefundRecvAccout    string    // 退款入账账户(微信的字段名就是 refund_recv_accout)
+	RefundAccount       string    // 退款资金来源
+	RefundRequestSource string    // 退款发起来源
+}
+
+// 解密退款结果通知中的 req_info.
+//  解密步骤:
+//  1. 对加密串 req_info 做 base64 解码, 得到加密串B;
+//  2. 对商户 API 密钥做 md5, 得到 32 位小写 key;
+//  3. 用 key 对加密串B做 AES-256-ECB 解密(PKCS7Padding).
+func DecryptReqInfo(reqInfo, apiKey string) (plaintext []byte, err error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(reqInfo)
+	if err != nil {
+		return
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		err = fmt.Errorf("the length of decoded req_info is invalid: %d", len(ciphertext))
+		return
+	}
+
+	keySum := md5.Sum([]byte(apiKey))
+	key := make([]byte, hex.EncodedLen(len(keySum)))
+	hex.Encode(key, keySum[:])
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return
+	}
+	plaintext = make([]byte, len(ciphertext))
+	for i := 0; i < len(ciphertext); i += aes.BlockSize {
+		block.Decrypt(plaintext[i:i+aes.BlockSize], ciphertext[i:i+aes.BlockSize])
+	}
+
+	// PKCS7 unpadding
+	amountToPad := int(plaintext[len(plaintext)-1])
+	if amountToPad < 1 || amountToPad > aes.BlockSize {
+		err = errors.New("invalid PKCS7 padding of req_info")
+		plaintext = nil
+		return
+	}
+	for _, b := range plaintext[len(plaintext)-amountToPad:] {
+		if int(b) != amountToPad {
+			err = errors.New("invalid PKCS7 padding of req_info")
+			plaintext = nil
+			return
+		}
+	}
+	plaintext = plaintext[:len(plaintext)-amountToPad]
+	return
+}
+
+// 从解密后的 req_info 键值集合解析出 RefundResult.
+func ParseRefundResult(reqInfo map[string]string) (result *RefundResult, err error) {
+	result = &RefundResult{
+		TransactionId:       reqInfo["transaction_id"],
+		OutTradeNo:          reqInfo["out_trade_no"],
+		RefundId:            reqInfo["refund_id"],
+		OutRefundNo:         reqInfo["out_refund_no"],
+		RefundStatus:        reqInfo["refund_status"],
+		RefundRecvAccout:    reqInfo["refund_recv_accout"],
+		RefundAccount:       reqInfo["refund_account"],
+		RefundRequestSource: reqInfo["refund_request_source"],
+	}
+	if result.TotalFee, err = parseInt64(reqInfo, "total_fee"); err != nil {
+		return nil, err
+	}
+	if result.SettlementTotalFee, err = parseInt64(reqInfo, "settlement_total_fee"); err != nil {
+		return nil, err
+	}
+	if result.RefundFee, err = parseInt64(reqInfo, "refund_fee"); err != nil {
+		return nil, err
+	}
+	if result.SettlementRefundFee, err = parseInt64(reqInfo, "settlement_refund_fee"); err != nil {
+		return nil, err
+	}
+	if result.SuccessTime, err = parseTime(reqInfo, "success_time", "2006-01-02 15:04:05"); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// 解析 m[key] 为 int64, m[key] 不存在或者为空时返回 0.
+func parseInt64(m map[string]string, key string) (n int64, err error) {
+	str := m[key]
+	if str == "" {
+		return
+	}
+	if n, err = strconv.ParseInt(str, 10, 64); err != nil {
+		err = fmt.Errorf("invalid %s: %q", key, str)
+		return
+	}
+	return
+}
+
+// 以北京时间解析 m[key] 为 time.Time, m[key] 不存在或者为空时返回零值.
+func parseTime(m map[string]string, key, layout string) (t time.Time, err error) {
+	str := m[key]
+	if str == "" {
+		return
+	}
+	if t, err = time.ParseInLocation(layout, str, util.BeijingLocation); err != nil {
+		err = fmt.Errorf("invalid %s: %q", key, str)
+		return
+	}
+	return
+}
+
+// 处理退款结果通知, 调用者需要先校验 appid 和 mch_id.
+//  解密或者解析出错都回复微信服务器 FAIL.
+func serveRefund(w http.ResponseWriter, r *http.Request, RawMsgXML []byte, msg map[string]string,
+	messageServer MessageServer) {
+
+	ReqInfoXML, err := DecryptReqInfo(msg["req_info"], messageServer.APIKey())
+	if err != nil {
+		ReplyFail(w, err.Error())
+		return
+	}
+	reqInfo, err := util.ParseXMLToMap(bytes.NewReader(ReqInfoXML))
+	if err != nil {
+		ReplyFail(w, err.Error())
+		return
+	}
+
+	srv, ok := messageServer.(RefundMessageServer)
+	if !ok {
+		req := &Request{
+			HttpRequest: r,
+
+			RawMsgXML: RawMsgXML,
+			Msg:       msg,
+			ReqInfo:   reqInfo,
+		}
+		messageServer.MessageHandler().ServeMessage(w, req)
+		return
+	}
+	refundHandler := srv.RefundHandler()
+	if refundHandler == nil {
+		ReplySuccess(w) // 没有处理退款结果通知, 不需要微信服务器重复通知
+		return
+	}
+
+	result, err := ParseRefundResult(reqInfo)
+	if err != nil {
+		ReplyFail(w, err.Error())
+		return
+	}
+	req := &RefundRequest{
+		HttpRequest: r,
+
+		RawMsgXML: RawMsgXML,
+		Msg:       msg,
+
+		ReqInfoXML: ReqInfoXML,
+		ReqInfo:    reqInfo,
+		Result:     result,
+	}
+	if err = refundHandler.ServeRefund(req); err != nil {
+		ReplyFail(w, err.Error())
+		return
+	}
+	ReplySuccess(w)
+}
@@ -0,0 +1,166 @@
+package pay
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chanxuehong/util"
+)
+
+// 按照微信支付的规则加密 req_info, AES-256-ECB(PKCS7Padding), key 为 md5(apiKey) 的小写 hex
+func encryptReqInfo(plaintext []byte, apiKey string) string {
+	keySum := md5.Sum([]byte(apiKey))
+	block, err := aes.NewCipher([]byte(hex.EncodeToString(keySum[:])))
+	if err != nil {
+		panic(err)
+	}
+
+	amountToPad := aes.BlockSize - len(plaintext)%aes.BlockSize
+	src := append(append([]byte(nil), plaintext...), bytes.Repeat([]byte{byte(amountToPad)}, amountToPad)...)
+	dst := make([]byte, len(src))
+	for i := 0; i < len(src); i += aes.BlockSize {
+		block.Encrypt(dst[i:i+aes.BlockSize], src[i:i+aes.BlockSize])
+	}
+	return base64.StdEncoding.EncodeToString(dst)
+}
+
+const refundTestReqInfo = `<root>
+<out_refund_no><![CDATA[131811191610442717309]]></out_refund_no>
+<out_trade_no><![CDATA[71106718111915575302817]]></out_trade_no>
+<refund_account><![CDATA[REFUND_SOURCE_RECHARGE_FUNDS]]></refund_account>
+<refund_fee><![CDATA[3960]]></refund_fee>
+<refund_id><![CDATA[50000408942018111907145868882]]></refund_id>
+<refund_recv_accout><![CDATA[支付用户零钱]]></refund_recv_accout>
+<refund_request_source><![CDATA[API]]></refund_request_source>
+<refund_status><![CDATA[SUCCESS]]></refund_status>
+<settlement_refund_fee><![CDATA[3960]]></settlement_refund_fee>
+<settlement_total_fee><![CDATA[3960]]></settlement_total_fee>
+<success_time><![CDATA[2018-11-19 16:24:13]]></success_time>
+<total_fee><![CDATA[3960]]></total_fee>
+<transaction_id><![CDATA[4200000215201811190261405420]]></transaction_id>
+</root>`
+
+func TestDecryptReqInfo(t *testing.T) {
+	apiKey := signTestAPIKey
+
+	plaintext, err := DecryptReqInfo(encryptReqInfo([]byte(refundTestReqInfo), apiKey), apiKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != refundTestReqInfo {
+		t.Errorf("DecryptReqInfo:\nhave %q\nwant %q\n", plaintext, refundTestReqInfo)
+	}
+
+	if _, err = DecryptReqInfo(encryptReqInfo([]byte(refundTestReqInfo), apiKey), "wrong key"); err == nil {
+		t.Error("DecryptReqInfo with wrong key: want error")
+	}
+}
+
+func TestServeRefund(t *testing.T) {
+	const (
+		appId  = "wxd930ea5d5a258f4f"
+		mchId  = "10000100"
+		apiKey = signTestAPIKey
+	)
+
+	var have *RefundResult
+	srv := NewDefaultMessageServer(appId, mchId, apiKey, MessageHandlerFunc(func(http.ResponseWriter, *Request) {
+		t.Error("refund notification should not be dispatched to MessageHandler")
+	}))
+	srv.SetRefundHandler(RefundHandlerFunc(func(r *RefundRequest) error {
+		have = r.Result
+		return nil
+	}))
+	frontend := NewMessageServerFrontend(srv, InvalidRequestHandlerFunc(func(_ http.ResponseWriter, _ *http.Request, err error) {
+		t.Errorf("ServeInvalidRequest: %v", err)
+	}))
+
+	var body bytes.Buffer
+	util.FormatMapToXML(&body, map[string]string{
+		"return_code": ReturnCodeSuccess,
+		"appid":       appId,
+		"mch_id":      mchId,
+		"nonce_str":   "TeqClE3i0mvn3DrK",
+		"req_info":    encryptReqInfo([]byte(refundTestReqInfo), apiKey),
+	})
+	w := httptest.NewRecorder()
+	frontend.ServeHTTP(w, httptest.NewRequest("POST", "/refund", &body))
+
+	if have == nil {
+		t.Fatal("RefundHandler not called")
+	}
+	want := RefundResult{
+		TransactionId:       "4200000215201811190261405420",
+		OutTradeNo:          "71106718111915575302817",
+		RefundId:            "50000408942018111907145868882",
+		OutRefundNo:         "131811191610442717309",
+		TotalFee:            3960,
+		SettlementTotalFee:  3960,
+		RefundFee:           3960,
+		SettlementRefundFee: 3960,
+		RefundStatus:        RefundStatusSuccess,
+		SuccessTime:         time.Date(2018, 11, 19, 16, 24, 13, 0, util.BeijingLocation),
+		RefundRecvAccout:    "支付用户零钱",
+		RefundAccount:       "REFUND_SOURCE_RECHARGE_FUNDS",
+		RefundRequestSource: "API",
+	}
+	if !have.SuccessTime.Equal(want.SuccessTime) {
+		t.Errorf("SuccessTime:\nhave %v\nwant %v\n", have.SuccessTime, want.SuccessTime)
+	}
+	have.SuccessTime = want.SuccessTime
+	if *have != want {
+		t.Errorf("RefundResult:\nhave %+v\nwant %+v\n", *have, want)
+	}
+	if !strings.Contains(w.Body.String(), "<return_code>SUCCESS</return_code>") {
+		t.Errorf("reply: %s", w.Body.String())
+	}
+}
+
+func TestServeRefundReply(t *testing.T) {
+	const (
+		appId  = "wxd930ea5d5a258f4f"
+		mchId  = "10000100"
+		apiKey = signTestAPIKey
+	)
+
+	srv := NewDefaultMessageServer(appId, mchId, apiKey, MessageHandlerFunc(func(http.ResponseWriter, *Request) {
+		t.Error("refund notification should not be dispatched to MessageHandler")
+	}))
+	frontend := NewMessageServerFrontend(srv, InvalidRequestHandlerFunc(func(_ http.ResponseWriter, _ *http.Request, err error) {
+		t.Errorf("ServeInvalidRequest: %v", err)
+	}))
+
+	tests := []struct {
+		reqInfo string
+		reply   string
+	}{
+		// 没有 RefundHandler 时直接回复 SUCCESS
+		{encryptReqInfo([]byte(refundTestReqInfo), apiKey), "<return_code>SUCCESS</return_code>"},
+		// 解密失败回复 FAIL
+		{encryptReqInfo([]byte(refundTestReqInfo), "wrong key"), "<return_code>FAIL</return_code>"},
+		{"invalid base64", "<return_code>FAIL</return_code>"},
+	}
+	for _, test := range tests {
+		var body bytes.Buffer
+		util.FormatMapToXML(&body, map[string]string{
+			"return_code": ReturnCodeSuccess,
+			"appid":       appId,
+			"mch_id":      mchId,
+			"nonce_str":   "TeqClE3i0mvn3DrK",
+			"req_info":    test.reqInfo,
+		})
+		w := httptest.NewRecorder()
+		frontend.ServeHTTP(w, httptest.NewRequest("POST", "/refund", &body))
+		if !strings.Contains(w.Body.String(), test.reply) {
+			t.Errorf("reply: have %s, want %s", w.Body.String(), test.reply)
+		}
+	}
+}
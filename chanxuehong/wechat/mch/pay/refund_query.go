@@ -0,0 +1,150 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package pay
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// 退款使用的代金券
+type RefundCoupon struct {
+	Type string // 代金券类型, CASH: 充值代金券, NO_CASH: 非充值代金券
+	Id   string // 退款代金券ID
+	Fee  int64  // 单个退款代金券支付金额, 单位为分
+}
+
+// 单笔退款, 对应退款查询结果中下标为 $n 的 refund_*_$n 等字段
+type RefundItem struct {
+	OutRefundNo         string    // 商户退款单号
+	RefundId            string    // 微信退款单号
+	RefundChannel       string    // 退款渠道, ORIGINAL: 原路退款, BALANCE: 退回到余额
+	RefundFee           int64     // 申请退款金额, 单位为分
+	SettlementRefundFee int64     // 退款金额, 退款金额=申请退款金额-非充值代金券退款金额
+	RefundStatus        string    // 退款状态, RefundStatusXXX
+	RefundAccount       string    // 退款资金来源
+	RefundRecvAccout    string    // 退款入账账户(微信的字段名就是 refund_recv_accout)
+	RefundSuccessTime   time.Time // 退款成功时间, 退款成功时才有, 否则为零值
+	CouponRefundFee     int64     // 代金券退款总金额, 单位为分
+	CouponRefundCount   int       // 退款代金券使用数量
+	Coupons             []RefundCoupon
+}
+
+// 退款是否已经有确定的结果, 即退款状态不是 PROCESSING.
+func (item *RefundItem) Settled() bool {
+	return item.RefundStatus != RefundStatusProcessing
+}
+
+// 退款查询结果
+type RefundQueryResult struct {
+	TransactionId      string // 微信订单号
+	OutTradeNo         string // 商户订单号
+	TotalFee           int64  // 订单金额, 单位为分
+	SettlementTotalFee int64  // 应结订单金额
+	FeeType            string // 货币种类
+	CashFee            int64  // 现金支付金额, 单位为分
+	TotalRefundCount   int    // 订单总共已发生的部分退款次数, 当请求参数传入 offset 后有返回
+	RefundCount        int    // 当前返回的退款笔数
+	Refunds            []RefundItem
+}
+
+// 解析退款查询(RefundQuery)的返回结果.
+func ParseRefundQueryResult(resp map[string]string) (result *RefundQueryResult, err error) {
+	result = &RefundQueryResult{
+		TransactionId: resp["transaction_id"],
+		OutTradeNo:    resp["out_trade_no"],
+		FeeType:       resp["fee_type"],
+	}
+	if result.TotalFee, err = parseInt64(resp, "total_fee"); err != nil {
+		return nil, err
+	}
+	if result.SettlementTotalFee, err = parseInt64(resp, "settlement_total_fee"); err != nil {
+		return nil, err
+	}
+	if result.CashFee, err = parseInt64(resp, "cash_fee"); err != nil {
+		return nil, err
+	}
+	if result.TotalRefundCount, err = parseCount(resp, "total_refund_count"); err != nil {
+		return nil, err
+	}
+	if result.RefundCount, err = parseCount(resp, "refund_count"); err != nil {
+		return nil, err
+	}
+
+	if result.RefundCount > 0 {
+		result.Refunds = make([]RefundItem, result.RefundCount)
+		for i := range result.Refunds {
+			if err = parseRefundItem(resp, strconv.Itoa(i), &result.Refunds[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return
+}
+
+// 查找商户退款单号为 outRefundNo 的退款, 没有找到返回 nil.
+func (result *RefundQueryResult) Find(outRefundNo string) *RefundItem {
+	for i := range result.Refunds {
+		if result.Refunds[i].OutRefundNo == outRefundNo {
+			return &result.Refunds[i]
+		}
+	}
+	return nil
+}
+
+func parseRefundItem(resp map[string]string, n string, item *RefundItem) (err error) {
+	item.OutRefundNo = resp["out_refund_no_"+n]
+	item.RefundId = resp["refund_id_"+n]
+	item.RefundChannel = resp["refund_channel_"+n]
+	item.RefundStatus = resp["refund_status_"+n]
+	item.RefundAccount = resp["refund_account_"+n]
+	item.RefundRecvAccout = resp["refund_recv_accout_"+n]
+
+	if item.RefundFee, err = parseInt64(resp, "refund_fee_"+n); err != nil {
+		return
+	}
+	if item.SettlementRefundFee, err = parseInt64(resp, "settlement_refund_fee_"+n); err != nil {
+		return
+	}
+	if item.RefundSuccessTime, err = parseTime(resp, "refund_success_time_"+n, "2006-01-02 15:04:05"); err != nil {
+		return
+	}
+	if item.CouponRefundFee, err = parseInt64(resp, "coupon_refund_fee_"+n); err != nil {
+		return
+	}
+	if item.CouponRefundCount, err = parseCount(resp, "coupon_refund_count_"+n); err != nil {
+		return
+	}
+
+	if item.CouponRefundCount > 0 {
+		item.Coupons = make([]RefundCoupon, item.CouponRefundCount)
+		for j := range item.Coupons {
+			m := n + "_" + strconv.Itoa(j)
+			coupon := &item.Coupons[j]
+			coupon.Type = resp["coupon_type_"+m]
+			coupon.Id = resp["coupon_refund_id_"+m]
+			if coupon.Fee, err = parseInt64(resp, "coupon_refund_fee_"+m); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// 解析 m[key] 为非负的计数, m[key] 不存在或者为空时返回 0.
+func parseCount(m map[string]string, key string) (n int, err error) {
+	n64, err := parseInt64(m, key)
+	if err != nil {
+		return
+	}
+	if n64 < 0 {
+		err = fmt.Errorf("invalid %s: %d", key, n64)
+		return
+	}
+	n = int(n64)
+	return
+}
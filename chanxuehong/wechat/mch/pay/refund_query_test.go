@@ -0,0 +1,72 @@
+package pay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chanxuehong/util"
+)
+
+func TestParseRefundQueryResult(t *testing.T) {
+	resp := map[string]string{
+		"return_code":    "SUCCESS",
+		"result_code":    "SUCCESS",
+		"transaction_id": "1008450740201411110005820873",
+		"out_trade_no":   "1415757673",
+		"total_fee":      "100",
+		"cash_fee":       "100",
+		"refund_count":   "2",
+
+		"out_refund_no_0":         "1415701182",
+		"refund_id_0":             "2008450740201411110000174436",
+		"refund_channel_0":        "ORIGINAL",
+		"refund_fee_0":            "30",
+		"refund_status_0":         "SUCCESS",
+		"refund_recv_accout_0":    "支付用户的零钱",
+		"refund_success_time_0":   "2016-07-25 15:26:26",
+		"coupon_refund_fee_0":     "10",
+		"coupon_refund_count_0":   "2",
+		"coupon_type_0_0":         "CASH",
+		"coupon_refund_id_0_0":    "10000",
+		"coupon_refund_fee_0_0":   "6",
+		"coupon_type_0_1":         "NO_CASH",
+		"coupon_refund_id_0_1":    "10001",
+		"coupon_refund_fee_0_1":   "4",
+		"out_refund_no_1":         "1415701183",
+		"refund_id_1":             "2008450740201411110000174437",
+		"refund_fee_1":            "20",
+		"settlement_refund_fee_1": "20",
+		"refund_status_1":         "PROCESSING",
+	}
+
+	result, err := ParseRefundQueryResult(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TotalFee != 100 || result.RefundCount != 2 || len(result.Refunds) != 2 {
+		t.Fatalf("ParseRefundQueryResult: %+v", result)
+	}
+
+	item := result.Find("1415701182")
+	if item == nil {
+		t.Fatal("Find(1415701182): not found")
+	}
+	if item.RefundFee != 30 || !item.Settled() || item.CouponRefundFee != 10 || len(item.Coupons) != 2 {
+		t.Errorf("refund 0: %+v", item)
+	}
+	if want := (RefundCoupon{"NO_CASH", "10001", 4}); item.Coupons[1] != want {
+		t.Errorf("refund 0 coupon 1:\nhave %+v\nwant %+v\n", item.Coupons[1], want)
+	}
+	if want := time.Date(2016, 7, 25, 15, 26, 26, 0, util.BeijingLocation); !item.RefundSuccessTime.Equal(want) {
+		t.Errorf("RefundSuccessTime:\nhave %v\nwant %v\n", item.RefundSuccessTime, want)
+	}
+
+	item = &result.Refunds[1]
+	if item.OutRefundNo != "1415701183" || item.Settled() || item.RefundFee != 20 || !item.RefundSuccessTime.IsZero() {
+		t.Errorf("refund 1: %+v", item)
+	}
+
+	if fee := refundedFee(result, map[string]int64{"1415701183": 20, "other": 15}); fee != 65 {
+		t.Errorf("refundedFee: have %d, want 65", fee)
+	}
+}
@@ -0,0 +1,268 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package pay
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	ErrRefundFeeExceeded = errors.New("the refund fee exceeds the refundable fee")
+	ErrRefundNotFound    = errors.New("refund not found")
+	ErrRefundNotSettled  = errors.New("the refund is still processing")
+	ErrRefundFeeMismatch = errors.New("the refund fee mismatches the existing refund with the same key")
+)
+
+// 退款查询每页最多返回的退款笔数, 超过时需要用 offset 分页查询
+const refundQueryPageSize = 10
+
+// 退款服务, 在 Client.Refund 和 Client.RefundQuery 的基础上:
+//  1. 根据业务的幂等键生成固定的 out_refund_no, 重复提交不会重复退款;
+//  2. 统计订单已经退款(包括处理中)的金额, 拒绝超过订单金额的部分退款;
+//  3. 轮询退款查询直到退款有确定的结果.
+//  NOTE: 正在提交的退款只在内存中记录, 多进程部署时同一订单的退款应该由同一个进程处理.
+type RefundService struct {
+	clt   *Client
+	appId string
+	mchId string
+
+	PollInterval    time.Duration // 轮询退款状态的初始间隔, 默认 5s, 之后每次翻倍
+	MaxPollInterval time.Duration // 轮询间隔的上限, 默认 60s
+
+	mutex    sync.Mutex
+	reserved map[string]map[string]int64 // out_trade_no --> out_refund_no --> refund_fee, 已经提交但是查询不到的退款
+}
+
+func NewRefundService(clt *Client, appId, mchId string) *RefundService {
+	if clt == nil {
+		panic("pay: nil Client")
+	}
+	return &RefundService{
+		clt:             clt,
+		appId:           appId,
+		mchId:           mchId,
+		PollInterval:    5 * time.Second,
+		MaxPollInterval: 60 * time.Second,
+		reserved:        make(map[string]map[string]int64),
+	}
+}
+
+// 根据商户订单号和业务的幂等键生成商户退款单号, 相同的参数总是生成相同的退款单号.
+func NewOutRefundNo(outTradeNo, key string) string {
+	sum := md5.Sum([]byte(outTradeNo + "\x00" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// 申请退款, 返回商户退款单号.
+//  outTradeNo: 商户订单号
+//  totalFee:   订单金额, 单位为分
+//  key:        业务的幂等键(比如售后单号), 相同的 key 不会重复退款, 见 NewOutRefundNo
+//  refundFee:  退款金额, 单位为分, 加上已经退款(包括处理中)的金额不能超过 totalFee, 否则返回 ErrRefundFeeExceeded
+//  extra:      可选; 其他请求参数, 如 refund_desc, notify_url, refund_account 等
+//  如果 key 已经退款过了, 不再提交退款, 返回 existing 为已经存在的退款, resp 为 nil;
+//  这时候如果 refundFee 和已经存在的退款金额不一致, 同时返回 ErrRefundFeeMismatch.
+//  NOTE: 请求需要双向证书.
+func (s *RefundService) Refund(outTradeNo string, totalFee int64, key string, refundFee int64,
+	extra map[string]string) (outRefundNo string, existing *RefundItem, resp map[string]string, err error) {
+
+	if refundFee <= 0 {
+		err = fmt.Errorf("invalid refund_fee: %d", refundFee)
+		return
+	}
+	outRefundNo = NewOutRefundNo(outTradeNo, key)
+
+	result, err := s.QueryRefunds(outTradeNo)
+	if err != nil {
+		return
+	}
+	if existing = result.Find(outRefundNo); existing != nil { // 已经退款过了
+		if existing.RefundFee != refundFee {
+			err = ErrRefundFeeMismatch
+		}
+		return
+	}
+
+	s.mutex.Lock()
+	reserved := s.reserved[outTradeNo]
+	if _, ok := reserved[outRefundNo]; ok {
+		s.mutex.Unlock()
+		err = errors.New("the refund is being submitted: " + outRefundNo)
+		return
+	}
+	refunded := refundedFee(result, reserved)
+	if refunded+refundFee > totalFee {
+		s.mutex.Unlock()
+		err = ErrRefundFeeExceeded
+		return
+	}
+	if reserved == nil {
+		reserved = make(map[string]int64)
+		s.reserved[outTradeNo] = reserved
+	}
+	reserved[outRefundNo] = refundFee
+	s.mutex.Unlock()
+
+	req := make(map[string]string, len(extra)+8)
+	for k, v := range extra {
+		req[k] = v
+	}
+	req["appid"] = s.appId
+	req["mch_id"] = s.mchId
+	req["nonce_str"] = NewNonceStr()
+	req["out_trade_no"] = outTradeNo
+	req["out_refund_no"] = outRefundNo
+	req["total_fee"] = strconv.FormatInt(totalFee, 10)
+	req["refund_fee"] = strconv.FormatInt(refundFee, 10)
+	setDefault(req, "op_user_id", s.mchId)
+
+	resp, err = s.clt.Refund(req)
+	switch {
+	case err != nil:
+		if _, ok := err.(*Error); ok {
+			s.unreserve(outTradeNo, outRefundNo) // 协议错误, 退款没有发生
+		}
+		// 网络错误等, 退款结果未知, 保留记录, 用相同的 key 重试不会重复退款;
+		// 调用 WaitRefund 确认退款的结果, 查询不到退款时会清除记录.
+		return
+	case resp["result_code"] != ResultCodeSuccess:
+		err = &ResultError{ErrCode: resp["err_code"], ErrCodeDes: resp["err_code_des"]}
+		if resp["err_code"] != "SYSTEMERROR" {
+			s.unreserve(outTradeNo, outRefundNo)
+		}
+		return
+	}
+	return
+}
+
+// 查询订单的所有退款, 订单没有退款时返回的 Refunds 为空.
+//  NOTE: 请求总是带上 offset 参数(从 0 开始), 因为只有这样才会返回 total_refund_count.
+func (s *RefundService) QueryRefunds(outTradeNo string) (result *RefundQueryResult, err error) {
+	for offset := 0; ; offset += refundQueryPageSize {
+		req := map[string]string{
+			"appid":        s.appId,
+			"mch_id":       s.mchId,
+			"nonce_str":    NewNonceStr(),
+			"out_trade_no": outTradeNo,
+			"offset":       strconv.Itoa(offset),
+		}
+
+		var page *RefundQueryResult
+		if page, err = s.queryRefund(req); err != nil {
+			return
+		}
+		if result == nil {
+			result = page
+		} else {
+			result.Refunds = append(result.Refunds, page.Refunds...)
+			result.RefundCount = len(result.Refunds)
+		}
+		if page.RefundCount < refundQueryPageSize {
+			return // 最后一页
+		}
+		if result.TotalRefundCount > 0 && len(result.Refunds) >= result.TotalRefundCount {
+			return
+		}
+	}
+}
+
+// 根据商户退款单号查询退款.
+func (s *RefundService) QueryRefund(outRefundNo string) (item *RefundItem, err error) {
+	req := map[string]string{
+		"appid":         s.appId,
+		"mch_id":        s.mchId,
+		"nonce_str":     NewNonceStr(),
+		"out_refund_no": outRefundNo,
+	}
+	result, err := s.queryRefund(req)
+	if err != nil {
+		return
+	}
+	if item = result.Find(outRefundNo); item == nil {
+		err = ErrRefundNotFound
+		return
+	}
+	return
+}
+
+// 轮询退款查询直到退款有确定的结果(退款状态不是 PROCESSING), 或者超过 timeout.
+//  超时返回最后一次查询的结果和 ErrRefundNotSettled;
+//  超时的时候仍然查询不到退款(比如 Refund 时网络错误, 退款没有提交成功), 则返回 ErrRefundNotFound,
+//  并且清除 Refund 保留的记录, 之后可以用相同的 key 重新调用 Refund(相同的 out_refund_no 不会重复退款).
+func (s *RefundService) WaitRefund(outTradeNo, outRefundNo string, timeout time.Duration) (item *RefundItem, err error) {
+	deadline := time.Now().Add(timeout)
+	interval := s.PollInterval
+	for {
+		item, err = s.QueryRefund(outRefundNo)
+		if err == nil && item.Settled() {
+			s.unreserve(outTradeNo, outRefundNo)
+			return
+		}
+		if !time.Now().Add(interval).Before(deadline) {
+			switch err {
+			case nil:
+				err = ErrRefundNotSettled
+			case ErrRefundNotFound:
+				s.unreserve(outTradeNo, outRefundNo)
+			}
+			return
+		}
+		time.Sleep(interval)
+		if interval *= 2; interval > s.MaxPollInterval {
+			interval = s.MaxPollInterval
+		}
+	}
+}
+
+// 查询退款, 订单没有退款(REFUNDNOTEXIST)时返回空的结果.
+func (s *RefundService) queryRefund(req map[string]string) (result *RefundQueryResult, err error) {
+	resp, err := s.clt.RefundQuery(req)
+	if err != nil {
+		return
+	}
+	if resp["result_code"] != ResultCodeSuccess {
+		if resp["err_code"] == "REFUNDNOTEXIST" {
+			result = &RefundQueryResult{}
+			return
+		}
+		err = &ResultError{ErrCode: resp["err_code"], ErrCodeDes: resp["err_code_des"]}
+		return
+	}
+	return ParseRefundQueryResult(resp)
+}
+
+func (s *RefundService) unreserve(outTradeNo, outRefundNo string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if reserved := s.reserved[outTradeNo]; reserved != nil {
+		delete(reserved, outRefundNo)
+		if len(reserved) == 0 {
+			delete(s.reserved, outTradeNo)
+		}
+	}
+}
+
+// 已经退款(包括处理中, 退款异常)的金额, 退款关闭的不计算在内.
+//  reserved 中已经能查询到的退款不重复计算.
+func refundedFee(result *RefundQueryResult, reserved map[string]int64) (fee int64) {
+	for i := range result.Refunds {
+		if result.Refunds[i].RefundStatus != RefundStatusRefundClose {
+			fee += result.Refunds[i].RefundFee
+		}
+	}
+	for outRefundNo, refundFee := range reserved {
+		if result.Find(outRefundNo) == nil {
+			fee += refundFee
+		}
+	}
+	return
+}
@@ -0,0 +1,140 @@
+package pay
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// 模拟有 n 笔部分退款(每笔 10 分)的订单的退款查询, 每页最多返回 10 笔, 传入 offset 才返回 total_refund_count.
+func handleRefundQuery(stub *stubAPI, n int) {
+	stub.Handle("/pay/refundquery", signTestAPIKey, func(req map[string]string) map[string]string {
+		offset, _ := strconv.Atoi(req["offset"])
+		if offset >= n {
+			return map[string]string{"result_code": ResultCodeFail, "err_code": "REFUNDNOTEXIST", "err_code_des": "退款订单查询失败"}
+		}
+
+		resp := map[string]string{
+			"result_code":  ResultCodeSuccess,
+			"out_trade_no": req["out_trade_no"],
+			"total_fee":    "1000",
+		}
+		if _, ok := req["offset"]; ok {
+			resp["total_refund_count"] = strconv.Itoa(n)
+		}
+		count := 0
+		for i := offset; i < n && count < refundQueryPageSize; i++ {
+			m := strconv.Itoa(count)
+			resp["out_refund_no_"+m] = "refund" + strconv.Itoa(i)
+			resp["refund_fee_"+m] = "10"
+			resp["refund_status_"+m] = RefundStatusSuccess
+			count++
+		}
+		resp["refund_count"] = strconv.Itoa(count)
+		return resp
+	})
+}
+
+func TestRefundServiceQueryRefunds(t *testing.T) {
+	for _, n := range []int{0, 3, 10, 23} {
+		stub := newStubAPI()
+		handleRefundQuery(stub, n)
+
+		s := NewRefundService(NewClient(signTestAPIKey, stub.HttpClient()), "wx2421b1c4370ec43b", "10000100")
+		result, err := s.QueryRefunds("1415701182")
+		stub.Close()
+		if err != nil {
+			t.Errorf("QueryRefunds(%d refunds): %v", n, err)
+			continue
+		}
+		if len(result.Refunds) != n {
+			t.Errorf("QueryRefunds(%d refunds): have %d refunds", n, len(result.Refunds))
+			continue
+		}
+		for i := range result.Refunds {
+			if want := "refund" + strconv.Itoa(i); result.Refunds[i].OutRefundNo != want {
+				t.Errorf("QueryRefunds(%d refunds) Refunds[%d]: have %s, want %s", n, i, result.Refunds[i].OutRefundNo, want)
+			}
+		}
+	}
+}
+
+func TestRefundServiceRefundFeeExceeded(t *testing.T) {
+	stub := newStubAPI()
+	defer stub.Close()
+
+	handleRefundQuery(stub, 23) // 已经退款 230 分
+	refunds := 0
+	stub.Handle("/secapi/pay/refund", signTestAPIKey, func(req map[string]string) map[string]string {
+		refunds++
+		return map[string]string{"result_code": ResultCodeSuccess, "out_refund_no": req["out_refund_no"], "refund_fee": req["refund_fee"]}
+	})
+
+	s := NewRefundService(NewClient(signTestAPIKey, stub.HttpClient()), "wx2421b1c4370ec43b", "10000100")
+	if _, _, _, err := s.Refund("1415701182", 240, "aftersale-1", 20, nil); err != ErrRefundFeeExceeded {
+		t.Errorf("Refund 20 of the remaining 10: have %v, want %v", err, ErrRefundFeeExceeded)
+	}
+	if _, _, _, err := s.Refund("1415701182", 240, "aftersale-2", 10, nil); err != nil {
+		t.Errorf("Refund 10 of the remaining 10: %v", err)
+	}
+	if refunds != 1 {
+		t.Errorf("refund requests: have %d, want 1", refunds)
+	}
+}
+
+func TestRefundServiceExistingRefund(t *testing.T) {
+	stub := newStubAPI()
+	defer stub.Close()
+
+	outRefundNo := NewOutRefundNo("1415701182", "aftersale-1")
+	stub.Handle("/pay/refundquery", signTestAPIKey, func(req map[string]string) map[string]string {
+		return map[string]string{
+			"result_code":     ResultCodeSuccess,
+			"out_trade_no":    "1415701182",
+			"total_fee":       "240",
+			"refund_count":    "1",
+			"out_refund_no_0": outRefundNo,
+			"refund_fee_0":    "20",
+			"refund_status_0": RefundStatusSuccess,
+		}
+	})
+	stub.Handle("/secapi/pay/refund", signTestAPIKey, func(req map[string]string) map[string]string {
+		t.Error("the existing refund should not be submitted again")
+		return map[string]string{"result_code": ResultCodeSuccess}
+	})
+
+	s := NewRefundService(NewClient(signTestAPIKey, stub.HttpClient()), "wx2421b1c4370ec43b", "10000100")
+	_, existing, resp, err := s.Refund("1415701182", 240, "aftersale-1", 20, nil)
+	if err != nil || resp != nil || existing == nil || existing.OutRefundNo != outRefundNo {
+		t.Errorf("Refund: %+v, %v, %v", existing, resp, err)
+	}
+	if _, existing, _, err = s.Refund("1415701182", 240, "aftersale-1", 30, nil); err != ErrRefundFeeMismatch || existing == nil {
+		t.Errorf("Refund with another fee: %+v, %v", existing, err)
+	}
+}
+
+// 网络错误时保留的记录, 在 WaitRefund 超时仍然查询不到退款时清除
+func TestRefundServiceTransportError(t *testing.T) {
+	stub := newStubAPI()
+	defer stub.Close()
+
+	handleRefundQuery(stub, 0)
+	s := NewRefundService(NewClient(signTestAPIKey, stub.HttpClient()), "wx2421b1c4370ec43b", "10000100")
+	s.PollInterval = time.Millisecond
+
+	// 没有注册 /secapi/pay/refund, 回复不是 XML, 相当于网络错误
+	outRefundNo, _, _, err := s.Refund("1415701182", 240, "aftersale-1", 20, nil)
+	if err == nil {
+		t.Fatal("Refund: want error")
+	}
+	if _, _, _, err = s.Refund("1415701182", 240, "aftersale-1", 20, nil); err == nil {
+		t.Error("Refund while the refund is reserved: want error")
+	}
+
+	if _, err = s.WaitRefund("1415701182", outRefundNo, 5*time.Millisecond); err != ErrRefundNotFound {
+		t.Errorf("WaitRefund: have %v, want %v", err, ErrRefundNotFound)
+	}
+	if n := len(s.reserved); n != 0 {
+		t.Errorf("reserved: %v", s.reserved)
+	}
+}
@@ -0,0 +1,36 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package pay
+
+import (
+	"net/http"
+
+	"github.com/chanxuehong/util"
+)
+
+// 回复微信服务器通知处理成功.
+//  <xml><return_code>SUCCESS</return_code><return_msg>OK</return_msg></xml>
+func ReplySuccess(w http.ResponseWriter) error {
+	return writeReply(w, ReturnCodeSuccess, "OK")
+}
+
+// 回复微信服务器通知处理失败, 微信服务器会稍后重新通知.
+//  <xml><return_code>FAIL</return_code><return_msg>msg</return_msg></xml>
+func ReplyFail(w http.ResponseWriter, msg string) error {
+	return writeReply(w, ReturnCodeFail, msg)
+}
+
+// 回复微信服务器的通知, returnMsg 为空时不输出 return_msg.
+func writeReply(w http.ResponseWriter, returnCode, returnMsg string) (err error) {
+	m := make(map[string]string, 2)
+	m["return_code"] = returnCode
+	if returnMsg != "" {
+		m["return_msg"] = returnMsg
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	return util.FormatMapToXML(w, m)
+}
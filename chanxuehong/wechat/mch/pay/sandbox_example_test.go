@@ -0,0 +1,333 @@
+package pay_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+
+	"github.com/chanxuehong/util"
+	"github.com/chanxuehong/wechat/internal/testutil"
+	"github.com/chanxuehong/wechat/mch/pay"
+)
+
+const (
+	sandboxAppId   = "wxd930ea5d5a258f4f"
+	sandboxMchId   = "10000100"
+	sandboxAPIKey  = "192006250b4c09247ec02edce69f6a2d"
+	sandboxSignKey = "d41d8cd98f00b204e9800998ecf8427e" // 本地模拟的 sandbox_signkey
+)
+
+// 本地模拟的仿真测试系统, 按照订单金额返回验收用例对应的结果:
+//  刷卡支付 5.01 元: 正常支付
+//  刷卡支付 5.02 元: 使用 0.01 元代金券支付
+//  公众号/扫码支付 5.51 元: 正常支付
+//  公众号/扫码支付 5.52 元: 使用 0.01 元代金券支付, 并且可以全额退款
+//
+//  NOTE: 下面的示例只演示验收用例的调用方式, 结果是这里写死的, 不能代替在微信的仿真测试系统上验收.
+//  模拟系统只校验沙箱的签名协议: 只接受 sandboxnew 下的地址, getsignkey 用 API密钥 签名,
+//  其他接口必须用 sandbox_signkey 做 MD5 签名, 回包也用 sandbox_signkey 签名.
+type sandboxStub struct {
+	*httptest.Server
+
+	mutex          sync.Mutex
+	getSignKeyHits int
+	orders         map[string]int64         // out_trade_no --> total_fee
+	refunds        map[string]sandboxRefund // out_trade_no --> 退款
+}
+
+type sandboxRefund struct {
+	outRefundNo string
+	refundFee   int64
+}
+
+func newSandboxStub() *sandboxStub {
+	stub := &sandboxStub{
+		orders:  make(map[string]int64),
+		refunds: make(map[string]sandboxRefund),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sandboxnew/pay/getsignkey", stub.serve(sandboxAPIKey, func(req map[string]string) map[string]string {
+		stub.getSignKeyHits++
+		return map[string]string{"mch_id": sandboxMchId, "sandbox_signkey": sandboxSignKey}
+	}))
+	mux.HandleFunc("/sandboxnew/pay/micropay", stub.serve(sandboxSignKey, func(req map[string]string) map[string]string {
+		totalFee, _ := strconv.ParseInt(req["total_fee"], 10, 64)
+		resp := map[string]string{"result_code": "SUCCESS", "out_trade_no": req["out_trade_no"], "total_fee": req["total_fee"]}
+		switch totalFee {
+		case 501:
+			resp["cash_fee"] = "501"
+		case 502:
+			resp["cash_fee"] = "501"
+			resp["coupon_fee"] = "1"
+		default:
+			return map[string]string{"result_code": "FAIL", "err_code": "PARAM_ERROR", "err_code_des": "未定义的验收用例"}
+		}
+		stub.orders[req["out_trade_no"]] = totalFee
+		return resp
+	}))
+	mux.HandleFunc("/sandboxnew/pay/unifiedorder", stub.serve(sandboxSignKey, func(req map[string]string) map[string]string {
+		totalFee, _ := strconv.ParseInt(req["total_fee"], 10, 64)
+		if totalFee != 551 && totalFee != 552 {
+			return map[string]string{"result_code": "FAIL", "err_code": "PARAM_ERROR", "err_code_des": "未定义的验收用例"}
+		}
+		stub.orders[req["out_trade_no"]] = totalFee
+		return map[string]string{"result_code": "SUCCESS", "trade_type": req["trade_type"], "prepay_id": "wx201411101639507cbf6ffd8b0779950874"}
+	}))
+	mux.HandleFunc("/sandboxnew/pay/orderquery", stub.serve(sandboxSignKey, func(req map[string]string) map[string]string {
+		totalFee, ok := stub.orders[req["out_trade_no"]]
+		if !ok {
+			return map[string]string{"result_code": "FAIL", "err_code": "ORDERNOTEXIST", "err_code_des": "此交易订单号不存在"}
+		}
+		resp := map[string]string{
+			"result_code":  "SUCCESS",
+			"trade_state":  pay.TradeStateSuccess,
+			"out_trade_no": req["out_trade_no"],
+			"total_fee":    strconv.FormatInt(totalFee, 10),
+			"cash_fee":     strconv.FormatInt(totalFee, 10),
+		}
+		if totalFee%2 == 0 {
+			resp["cash_fee"] = strconv.FormatInt(totalFee-1, 10)
+			resp["coupon_fee"] = "1"
+		}
+		if _, ok := stub.refunds[req["out_trade_no"]]; ok {
+			resp["trade_state"] = pay.TradeStateRefund
+		}
+		return resp
+	}))
+	mux.HandleFunc("/sandboxnew/pay/refund", stub.serve(sandboxSignKey, func(req map[string]string) map[string]string {
+		totalFee, ok := stub.orders[req["out_trade_no"]]
+		if !ok || totalFee != 552 {
+			return map[string]string{"result_code": "FAIL", "err_code": "PARAM_ERROR", "err_code_des": "未定义的验收用例"}
+		}
+		refundFee, _ := strconv.ParseInt(req["refund_fee"], 10, 64)
+		stub.refunds[req["out_trade_no"]] = sandboxRefund{outRefundNo: req["out_refund_no"], refundFee: refundFee}
+		return map[string]string{"result_code": "SUCCESS", "out_refund_no": req["out_refund_no"], "refund_fee": req["refund_fee"]}
+	}))
+	mux.HandleFunc("/sandboxnew/pay/refundquery", stub.serve(sandboxSignKey, func(req map[string]string) map[string]string {
+		refund, ok := stub.refunds[req["out_trade_no"]]
+		if !ok {
+			return map[string]string{"result_code": "FAIL", "err_code": "REFUNDNOTEXIST", "err_code_des": "退款订单查询失败"}
+		}
+		return map[string]string{
+			"result_code":           "SUCCESS",
+			"out_trade_no":          req["out_trade_no"],
+			"total_fee":             strconv.FormatInt(stub.orders[req["out_trade_no"]], 10),
+			"refund_count":          "1",
+			"out_refund_no_0":       refund.outRefundNo,
+			"refund_fee_0":          strconv.FormatInt(refund.refundFee, 10),
+			"refund_status_0":       pay.RefundStatusSuccess,
+			"refund_success_time_0": "2018-02-01 10:00:00",
+		}
+	}))
+
+	stub.Server = httptest.NewServer(mux)
+	return stub
+}
+
+// 校验请求的签名, 调用 fn 处理请求, 回包用 signKey 签名.
+func (stub *sandboxStub) serve(signKey string, fn func(req map[string]string) map[string]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := util.ParseXMLToMap(r.Body)
+		if err != nil {
+			util.FormatMapToXML(w, map[string]string{"return_code": "FAIL", "return_msg": err.Error()})
+			return
+		}
+		if signType, ok := req["sign_type"]; ok && signType != pay.SignTypeMD5 {
+			util.FormatMapToXML(w, map[string]string{"return_code": "FAIL", "return_msg": "仿真测试系统只支持 MD5 签名"})
+			return
+		}
+		if err = pay.CheckSignature(req, signKey, pay.SignTypeMD5); err != nil {
+			util.FormatMapToXML(w, map[string]string{"return_code": "FAIL", "return_msg": "签名错误"})
+			return
+		}
+
+		stub.mutex.Lock()
+		resp := fn(req)
+		stub.mutex.Unlock()
+
+		resp["return_code"] = "SUCCESS"
+		resp["return_msg"] = "OK"
+		resp["appid"] = req["appid"]
+		resp["mch_id"] = sandboxMchId
+		resp["nonce_str"] = pay.NewNonceStr()
+		resp["sign"] = pay.SignMD5(resp, signKey)
+		util.FormatMapToXML(w, resp)
+	}
+}
+
+// 把发往 api.mch.weixin.qq.com 的请求转发到本地模拟的仿真测试系统.
+func (stub *sandboxStub) HttpClient() *http.Client {
+	return testutil.NewRedirectHttpClient(stub.Listener.Addr().String())
+}
+
+// 验收用例(只演示调用方式): 刷卡支付 5.01 元(正常) 和 5.02 元(使用代金券).
+func ExampleNewSandboxClient_microPay() {
+	stub := newSandboxStub()
+	defer stub.Close()
+
+	clt := pay.NewSandboxClient(sandboxMchId, sandboxAPIKey, stub.HttpClient())
+	for _, totalFee := range []string{"501", "502"} {
+		resp, err := clt.MicroPay(map[string]string{
+			"appid":            sandboxAppId,
+			"mch_id":           sandboxMchId,
+			"nonce_str":        pay.NewNonceStr(),
+			"body":             "sandbox",
+			"out_trade_no":     "micropay" + totalFee,
+			"total_fee":        totalFee,
+			"spbill_create_ip": "127.0.0.1",
+			"auth_code":        "120061098828009406",
+		})
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("total_fee=%s result_code=%s cash_fee=%s coupon_fee=%s\n",
+			totalFee, resp["result_code"], resp["cash_fee"], resp["coupon_fee"])
+	}
+	fmt.Println("getsignkey requests:", stub.getSignKeyHits)
+
+	// Output:
+	// total_fee=501 result_code=SUCCESS cash_fee=501 coupon_fee=
+	// total_fee=502 result_code=SUCCESS cash_fee=501 coupon_fee=1
+	// getsignkey requests: 1
+}
+
+// 验收用例(只演示调用方式): 公众号支付 5.51 元(正常) 和 5.52 元(使用代金券), 下单后查询订单.
+func ExampleNewSandboxClient_unifiedOrder() {
+	stub := newSandboxStub()
+	defer stub.Close()
+
+	clt := pay.NewSandboxClient(sandboxMchId, sandboxAPIKey, stub.HttpClient())
+	for _, totalFee := range []string{"551", "552"} {
+		outTradeNo := "jsapi" + totalFee
+		resp, err := clt.UnifiedOrder(map[string]string{
+			"appid":            sandboxAppId,
+			"mch_id":           sandboxMchId,
+			"nonce_str":        pay.NewNonceStr(),
+			"body":             "sandbox",
+			"out_trade_no":     outTradeNo,
+			"total_fee":        totalFee,
+			"spbill_create_ip": "127.0.0.1",
+			"notify_url":       "http://example.com/pay/notify",
+			"trade_type":       pay.TradeTypeJSAPI,
+			"openid":           "oUpF8uMuAJO_M2pxb1Q9zNjWeS6o",
+		})
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if _, err = pay.NewJSAPIPayParameters(resp, sandboxSignKey, pay.SignTypeMD5); err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		resp, err = clt.OrderQuery(map[string]string{
+			"appid":        sandboxAppId,
+			"mch_id":       sandboxMchId,
+			"nonce_str":    pay.NewNonceStr(),
+			"out_trade_no": outTradeNo,
+		})
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		result, err := pay.ParsePayResult(resp)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("total_fee=%d trade_state=%s cash_fee=%d coupon_fee=%d\n",
+			result.TotalFee, resp["trade_state"], result.CashFee, result.CouponFee)
+	}
+
+	// Output:
+	// total_fee=551 trade_state=SUCCESS cash_fee=551 coupon_fee=0
+	// total_fee=552 trade_state=SUCCESS cash_fee=551 coupon_fee=1
+}
+
+// 验收用例(只演示调用方式): 公众号支付 5.52 元后全额退款, 然后查询退款.
+func ExampleNewSandboxClient_refund() {
+	stub := newSandboxStub()
+	defer stub.Close()
+
+	clt := pay.NewSandboxClient(sandboxMchId, sandboxAPIKey, stub.HttpClient())
+	_, err := clt.UnifiedOrder(map[string]string{
+		"appid":            sandboxAppId,
+		"mch_id":           sandboxMchId,
+		"nonce_str":        pay.NewNonceStr(),
+		"body":             "sandbox",
+		"out_trade_no":     "refund552",
+		"total_fee":        "552",
+		"spbill_create_ip": "127.0.0.1",
+		"notify_url":       "http://example.com/pay/notify",
+		"trade_type":       pay.TradeTypeNative,
+		"product_id":       "552",
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resp, err := clt.Refund(map[string]string{
+		"appid":         sandboxAppId,
+		"mch_id":        sandboxMchId,
+		"nonce_str":     pay.NewNonceStr(),
+		"out_trade_no":  "refund552",
+		"out_refund_no": "refund552R",
+		"total_fee":     "552",
+		"refund_fee":    "552",
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("refund:", resp["result_code"])
+
+	resp, err = clt.RefundQuery(map[string]string{
+		"appid":        sandboxAppId,
+		"mch_id":       sandboxMchId,
+		"nonce_str":    pay.NewNonceStr(),
+		"out_trade_no": "refund552",
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	result, err := pay.ParseRefundQueryResult(resp)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	item := result.Find("refund552R")
+	fmt.Printf("refund_fee=%d refund_status=%s settled=%v\n", item.RefundFee, item.RefundStatus, item.Settled())
+
+	// Output:
+	// refund: SUCCESS
+	// refund_fee=552 refund_status=SUCCESS settled=true
+}
+
+// 模拟系统校验沙箱的签名协议: API密钥 错误时获取不到 sandbox_signkey, 所有接口都会失败.
+func ExampleNewSandboxClient_signKey() {
+	stub := newSandboxStub()
+	defer stub.Close()
+
+	clt := pay.NewSandboxClient(sandboxMchId, "wrong api key", stub.HttpClient())
+	_, err := clt.OrderQuery(map[string]string{
+		"appid":        sandboxAppId,
+		"mch_id":       sandboxMchId,
+		"nonce_str":    pay.NewNonceStr(),
+		"out_trade_no": "jsapi551",
+	})
+	fmt.Println(err)
+
+	clt = pay.NewSandboxClient(sandboxMchId, sandboxAPIKey, stub.HttpClient())
+	signKey, err := clt.SandboxSignKey()
+	fmt.Println(signKey == sandboxSignKey, err)
+
+	// Output:
+	// return_code: "FAIL", return_msg: "签名错误"
+	// true <nil>
+}
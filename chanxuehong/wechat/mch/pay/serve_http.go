@@ -36,47 +36,19 @@ func ServeHTTP(w http.ResponseWriter, r *http.Request, urlValues url.Values,
 
 		ReturnCode, ok := msg["return_code"]
 		if !ok || ReturnCode == ReturnCodeSuccess {
-			haveAppId := msg["appid"]
-			wantAppId := messageServer.AppId()
-			if len(haveAppId) != len(wantAppId) {
-				err = fmt.Errorf("the message's appid mismatch, have: %s, want: %s", haveAppId, wantAppId)
-				invalidRequestHandler.ServeInvalidRequest(w, r, err)
-				return
-			}
-			if subtle.ConstantTimeCompare([]byte(haveAppId), []byte(wantAppId)) != 1 {
-				err = fmt.Errorf("the message's appid mismatch, have: %s, want: %s", haveAppId, wantAppId)
+			if err = checkAppIdMchId(msg, messageServer.AppId(), messageServer.MchId()); err != nil {
 				invalidRequestHandler.ServeInvalidRequest(w, r, err)
 				return
 			}
 
-			haveMchId := msg["mch_id"]
-			wantMchId := messageServer.MchId()
-			if len(haveMchId) != len(wantMchId) {
-				err = fmt.Errorf("the message's mch_id mismatch, have: %s, want: %s", haveMchId, wantMchId)
-				invalidRequestHandler.ServeInvalidRequest(w, r, err)
-				return
-			}
-			if subtle.ConstantTimeCompare([]byte(haveMchId), []byte(wantMchId)) != 1 {
-				err = fmt.Errorf("the message's mch_id mismatch, have: %s, want: %s", haveMchId, wantMchId)
-				invalidRequestHandler.ServeInvalidRequest(w, r, err)
+			// 退款结果通知没有 sign, 其内容在加密的 req_info 中
+			if _, ok := msg["req_info"]; ok {
+				serveRefund(w, r, RawMsgXML, msg, messageServer)
 				return
 			}
 
-			// 认证签名
-			signature1, ok := msg["sign"]
-			if !ok {
-				err = errors.New("no sign parameter")
-				invalidRequestHandler.ServeInvalidRequest(w, r, err)
-				return
-			}
-			signature2 := Sign(msg, messageServer.APIKey(), nil)
-			if len(signature1) != len(signature2) {
-				err = fmt.Errorf("check signature failed, \r\ninput: %q, \r\nlocal: %q", signature1, signature2)
-				invalidRequestHandler.ServeInvalidRequest(w, r, err)
-				return
-			}
-			if subtle.ConstantTimeCompare([]byte(signature1), []byte(signature2)) != 1 {
-				err = fmt.Errorf("check signature failed, \r\ninput: %q, \r\nlocal: %q", signature1, signature2)
+			// 认证签名, 以消息声明的 sign_type 为准
+			if err = CheckSignature(msg, messageServer.APIKey(), msg["sign_type"]); err != nil {
 				invalidRequestHandler.ServeInvalidRequest(w, r, err)
 				return
 			}
@@ -94,3 +66,27 @@ func ServeHTTP(w http.ResponseWriter, r *http.Request, urlValues url.Values,
 		invalidRequestHandler.ServeInvalidRequest(w, r, errors.New("Request.Method: "+r.Method))
 	}
 }
+
+// 校验消息的 appid 和 mch_id.
+func checkAppIdMchId(msg map[string]string, wantAppId, wantMchId string) (err error) {
+	haveAppId := msg["appid"]
+	if len(haveAppId) != len(wantAppId) {
+		err = fmt.Errorf("the message's appid mismatch, have: %s, want: %s", haveAppId, wantAppId)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(haveAppId), []byte(wantAppId)) != 1 {
+		err = fmt.Errorf("the message's appid mismatch, have: %s, want: %s", haveAppId, wantAppId)
+		return
+	}
+
+	haveMchId := msg["mch_id"]
+	if len(haveMchId) != len(wantMchId) {
+		err = fmt.Errorf("the message's mch_id mismatch, have: %s, want: %s", haveMchId, wantMchId)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(haveMchId), []byte(wantMchId)) != 1 {
+		err = fmt.Errorf("the message's mch_id mismatch, have: %s, want: %s", haveMchId, wantMchId)
+		return
+	}
+	return
+}
@@ -7,8 +7,13 @@ package pay
 
 import (
 	"bytes"
+	"crypto/hmac"
 	"crypto/md5"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"hash"
 	"sort"
 )
@@ -17,6 +22,7 @@ import (
 //  parameters: 待签名的参数集合
 //  apiKey:     API密钥
 //  fn:         func() hash.Hash, 如果 fn == nil 则默认用 md5.New
+//  NOTE: fn 只是对 "k1=v1&k2=v2&key=apiKey" 做摘要, HMAC-SHA256 签名请用 SignHMACSHA256.
 func Sign(parameters map[string]string, apiKey string, fn func() hash.Hash) string {
 	ks := make([]string, 0, len(parameters))
 	for k := range parameters {
@@ -49,3 +55,48 @@ func Sign(parameters map[string]string, apiKey string, fn func() hash.Hash) stri
 	hex.Encode(signature, h.Sum(nil))
 	return string(bytes.ToUpper(signature))
 }
+
+// 微信支付 MD5 签名.
+func SignMD5(parameters map[string]string, apiKey string) string {
+	return Sign(parameters, apiKey, md5.New)
+}
+
+// 微信支付 HMAC-SHA256 签名.
+//  待签名的字符串同 MD5 签名, 即 "k1=v1&k2=v2&key=apiKey", 并且以 apiKey 作为 HMAC 的密钥.
+func SignHMACSHA256(parameters map[string]string, apiKey string) string {
+	return Sign(parameters, apiKey, func() hash.Hash {
+		return hmac.New(sha256.New, []byte(apiKey))
+	})
+}
+
+// 根据签名类型进行微信支付签名.
+//  signType: SignTypeMD5, SignTypeHMACSHA256, 为空时默认为 SignTypeMD5
+func SignWithType(parameters map[string]string, apiKey, signType string) (signature string, err error) {
+	switch signType {
+	case "", SignTypeMD5:
+		signature = SignMD5(parameters, apiKey)
+	case SignTypeHMACSHA256:
+		signature = SignHMACSHA256(parameters, apiKey)
+	default:
+		err = fmt.Errorf("unsupported sign_type: %q", signType)
+	}
+	return
+}
+
+// 校验参数集合中的 sign 参数.
+//  signType: SignTypeMD5, SignTypeHMACSHA256, 为空时默认为 SignTypeMD5
+func CheckSignature(parameters map[string]string, apiKey, signType string) (err error) {
+	signature1, ok := parameters["sign"]
+	if !ok {
+		return errors.New("no sign parameter")
+	}
+	signature2, err := SignWithType(parameters, apiKey, signType)
+	if err != nil {
+		return
+	}
+	if len(signature1) != len(signature2) ||
+		subtle.ConstantTimeCompare([]byte(signature1), []byte(signature2)) != 1 {
+		return fmt.Errorf("check signature failed, \r\ninput: %q, \r\nlocal: %q", signature1, signature2)
+	}
+	return
+}
@@ -0,0 +1,80 @@
+package pay
+
+import (
+	"testing"
+)
+
+// 微信支付文档(安全规范 - 签名算法)中的示例
+var signTestParameters = map[string]string{
+	"appid":       "wxd930ea5d5a258f4f",
+	"mch_id":      "10000100",
+	"device_info": "1000",
+	"body":        "test",
+	"nonce_str":   "ibuaiVcKdpRxkhJA",
+}
+
+const signTestAPIKey = "192006250b4c09247ec02edce69f6a2d"
+
+func TestSignMD5(t *testing.T) {
+	want := "9A0A8659F005D6984697E2CA0A9CF3B7"
+
+	if have := SignMD5(signTestParameters, signTestAPIKey); have != want {
+		t.Errorf("SignMD5:\nhave %s\nwant %s\n", have, want)
+	}
+	if have := Sign(signTestParameters, signTestAPIKey, nil); have != want {
+		t.Errorf("Sign:\nhave %s\nwant %s\n", have, want)
+	}
+}
+
+func TestSignHMACSHA256(t *testing.T) {
+	want := "6A9AE1657590FD6257D693A078E1C3E4BB6BA4DC30B23E0EE2496E54170DACD6"
+
+	if have := SignHMACSHA256(signTestParameters, signTestAPIKey); have != want {
+		t.Errorf("SignHMACSHA256:\nhave %s\nwant %s\n", have, want)
+	}
+}
+
+func TestSignWithType(t *testing.T) {
+	tests := []struct {
+		signType string
+		want     string
+	}{
+		{"", "9A0A8659F005D6984697E2CA0A9CF3B7"},
+		{SignTypeMD5, "9A0A8659F005D6984697E2CA0A9CF3B7"},
+		{SignTypeHMACSHA256, "6A9AE1657590FD6257D693A078E1C3E4BB6BA4DC30B23E0EE2496E54170DACD6"},
+	}
+	for _, test := range tests {
+		have, err := SignWithType(signTestParameters, signTestAPIKey, test.signType)
+		if err != nil {
+			t.Errorf("SignWithType(%q): %v", test.signType, err)
+			continue
+		}
+		if have != test.want {
+			t.Errorf("SignWithType(%q):\nhave %s\nwant %s\n", test.signType, have, test.want)
+		}
+	}
+
+	if _, err := SignWithType(signTestParameters, signTestAPIKey, "SHA1"); err == nil {
+		t.Error("SignWithType(\"SHA1\"): want error")
+	}
+}
+
+func TestCheckSignature(t *testing.T) {
+	m := make(map[string]string, len(signTestParameters)+2)
+	for k, v := range signTestParameters {
+		m[k] = v
+	}
+	m["sign_type"] = SignTypeHMACSHA256
+	m["sign"] = SignHMACSHA256(m, signTestAPIKey)
+
+	if err := CheckSignature(m, signTestAPIKey, m["sign_type"]); err != nil {
+		t.Errorf("CheckSignature(HMAC-SHA256): %v", err)
+	}
+	if err := CheckSignature(m, signTestAPIKey, SignTypeMD5); err == nil {
+		t.Error("CheckSignature(MD5) on HMAC-SHA256 signature: want error")
+	}
+	delete(m, "sign")
+	if err := CheckSignature(m, signTestAPIKey, SignTypeHMACSHA256); err == nil {
+		t.Error("CheckSignature without sign: want error")
+	}
+}
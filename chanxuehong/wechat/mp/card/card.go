@@ -135,6 +135,62 @@ func (clt *Client) CardBatchGet(offset, count int) (cardIdList []string, err err
 	return
 }
 
+const (
+	CardPageSizeLimit = 50 // 批量查询卡列表每次最多查询50 个
+
+	// 卡券状态
+	CardStatusNotVerify  = "CARD_STATUS_NOT_VERIFY"  // 待审核
+	CardStatusVerifyFail = "CARD_STATUS_VERIFY_FAIL" // 审核失败
+	CardStatusVerifyOK   = "CARD_STATUS_VERIFY_OK"   // 通过审核
+	CardStatusDelete     = "CARD_STATUS_DELETE"      // 卡券被商户删除
+	CardStatusDispatch   = "CARD_STATUS_DISPATCH"    // 在公众平台投放过的卡券
+)
+
+// 批量查询卡列表, 支持按照卡券状态筛选.
+//  offset:     查询卡列表的起始偏移量，从0 开始，即offset: 5 是指从从列表里的第六个开始读取。
+//  count :     需要查询的卡片的数量（数量最大50）
+//  statusList: 可选; 拉出指定状态的卡券列表, 为空时拉出所有状态的卡券
+//  totalNum:   该商户名下符合条件的卡券ID 总数
+func (clt *Client) CardBatchGetByStatus(offset, count int, statusList []string) (cardIdList []string, totalNum int, err error) {
+	if offset < 0 {
+		err = fmt.Errorf("invalid offset: %d", offset)
+		return
+	}
+	if count < 0 {
+		err = fmt.Errorf("invalid count: %d", count)
+		return
+	}
+
+	var request = struct {
+		Offset     int      `json:"offset"`
+		Count      int      `json:"count"`
+		StatusList []string `json:"status_list,omitempty"`
+	}{
+		Offset:     offset,
+		Count:      count,
+		StatusList: statusList,
+	}
+
+	var result struct {
+		mp.Error
+		CardIdList []string `json:"card_id_list"`
+		TotalNum   int      `json:"total_num"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/card/batchget?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	cardIdList = result.CardIdList
+	totalNum = result.TotalNum
+	return
+}
+
 // 更改卡券信息接口.
 //  支持更新部分通用字段及特殊卡券（会员卡、飞机票、电影票、红包）中特定字段的信息。
 //  注：更改卡券的部分字段后会重新提交审核，详情见字段说明。
@@ -0,0 +1,123 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     gaowenbin(gaowenbinmarr@gmail.com), chanxuehong(chanxuehong@gmail.com)
+
+package card
+
+import (
+	"fmt"
+
+	"github.com/chanxuehong/wechat/mp"
+)
+
+const (
+	CodeDepositSizeLimit = 100 // 导入code 接口每次最多导入100 个code
+	CodeCheckSizeLimit   = 100 // 核查code 接口每次最多查询100 个code
+)
+
+type CardCodeDepositResult struct {
+	SuccCode      []string `json:"succ_code"`      // 成功的code
+	DuplicateCode []string `json:"duplicate_code"` // 重复导入的code，会自动被过滤
+	FailCode      []string `json:"fail_code"`      // 失败的code
+}
+
+// 导入code.
+//  在自定义code 卡券成功创建并且通过审核后，必须将自定义code 按照与发券方的约定数量调用导入code 接口导入微信后台。
+//  cardId: 需要进行导入code 的卡券ID
+//  codes:  需导入微信卡券后台的自定义code，上限为100 个
+func (clt *Client) CardCodeDeposit(cardId string, codes []string) (rst *CardCodeDepositResult, err error) {
+	if len(codes) > CodeDepositSizeLimit {
+		err = fmt.Errorf("the length of codes exceeds %d", CodeDepositSizeLimit)
+		return
+	}
+
+	var request = struct {
+		CardId string   `json:"card_id"`
+		Code   []string `json:"code"`
+	}{
+		CardId: cardId,
+		Code:   codes,
+	}
+
+	var result struct {
+		mp.Error
+		CardCodeDepositResult
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/card/code/deposit?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	rst = &result.CardCodeDepositResult
+	return
+}
+
+// 查询导入code 数目.
+func (clt *Client) CardCodeGetDepositCount(cardId string) (count int, err error) {
+	var request = struct {
+		CardId string `json:"card_id"`
+	}{
+		CardId: cardId,
+	}
+
+	var result struct {
+		mp.Error
+		Count int `json:"count"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/card/code/getdepositcount?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	count = result.Count
+	return
+}
+
+// 核查code.
+//  为了避免出现导入差错，强烈建议开发者在查询完code 数目的时候核查code 接口校验code 导入微信后台的情况。
+//  cardId: 进行导入code 的卡券ID
+//  codes:  已经微信卡券后台的自定义code，上限为100 个
+func (clt *Client) CardCodeCheck(cardId string, codes []string) (existCode, notExistCode []string, err error) {
+	if len(codes) > CodeCheckSizeLimit {
+		err = fmt.Errorf("the length of codes exceeds %d", CodeCheckSizeLimit)
+		return
+	}
+
+	var request = struct {
+		CardId string   `json:"card_id"`
+		Code   []string `json:"code"`
+	}{
+		CardId: cardId,
+		Code:   codes,
+	}
+
+	var result struct {
+		mp.Error
+		ExistCode    []string `json:"exist_code"`
+		NotExistCode []string `json:"not_exist_code"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/card/code/checkcode?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	existCode = result.ExistCode
+	notExistCode = result.NotExistCode
+	return
+}
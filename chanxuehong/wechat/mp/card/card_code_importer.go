@@ -0,0 +1,242 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     gaowenbin(gaowenbinmarr@gmail.com), chanxuehong(chanxuehong@gmail.com)
+
+package card
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+const (
+	// 导入code 的结果状态
+	CodeImportStatusOK        = "ok"        // 导入成功并且核查存在
+	CodeImportStatusDuplicate = "duplicate" // 重复导入(核查存在), 或者输入中重复出现的 code(不再导入)
+	CodeImportStatusFail      = "fail"      // 导入失败
+	CodeImportStatusNotExist  = "not_exist" // 导入接口返回成功, 但是核查不存在
+
+	// 导入接口返回成功, 但是核查code 接口出错, 没有核查; 微信已经有这些 code, 所以也计入库存
+	CodeImportStatusUnverified = "unverified"
+)
+
+// 一个 code 的导入结果
+type CodeImportResult struct {
+	Code   string `json:"code"`
+	Status string `json:"status"` // 导入状态, CodeImportStatusXXX
+}
+
+// 导入 code 的报告
+type CodeImportReport struct {
+	CardId        string             `json:"card_id"`
+	Results       []CodeImportResult `json:"results"`        // 每个 code 的导入结果, 顺序和读取的顺序一致
+	IncreaseStock int                `json:"increase_stock"` // 增加的库存数量
+}
+
+// 按照状态统计 code 的数量
+func (report *CodeImportReport) Count(status string) (n int) {
+	for i := 0; i < len(report.Results); i++ {
+		if report.Results[i].Status == status {
+			n++
+		}
+	}
+	return
+}
+
+// 自定义 code 卡券的 code 导入器.
+//  从 io.Reader 中按行读取 code, 分批调用导入code 接口导入, 然后调用核查code 接口校验,
+//  最后根据核查成功的新导入的 code 数量增加卡券的库存.
+//
+//  importer := card.NewCodeImporter(clt, cardId)
+//  report, err := importer.Import(reader)
+//  if err != nil {
+//      // TODO: 增加你的代码, 注意 report 可能不为 nil, 包含了出错之前的导入结果
+//  }
+//  // TODO: 增加你的代码
+type CodeImporter struct {
+	wechatClient *Client // 关联的微信 Client
+	cardId       string  // 导入 code 的卡券ID
+
+	// 每批导入的 code 数量, 默认为 CodeDepositSizeLimit
+	ChunkSize int
+	// 是否根据核查成功的新导入的 code 数量增加卡券库存, 默认为 true
+	ModifyStock bool
+}
+
+func NewCodeImporter(clt *Client, cardId string) *CodeImporter {
+	if clt == nil {
+		panic("nil Client")
+	}
+	return &CodeImporter{
+		wechatClient: clt,
+		cardId:       cardId,
+		ChunkSize:    CodeDepositSizeLimit,
+		ModifyStock:  true,
+	}
+}
+
+// 从 r 中读取 code, 每行一个, 忽略空行和首尾的空白.
+func ReadCodes(r io.Reader) (codes []string, err error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		code := strings.TrimSpace(scanner.Text())
+		if code == "" {
+			continue
+		}
+		codes = append(codes, code)
+	}
+	if err = scanner.Err(); err != nil {
+		codes = nil
+		return
+	}
+	return
+}
+
+// 导入 r 中的 code, 每行一个.
+//  如果 err != nil, report 包含了出错之前已经处理的 code 的结果.
+func (importer *CodeImporter) Import(r io.Reader) (report *CodeImportReport, err error) {
+	codes, err := ReadCodes(r)
+	if err != nil {
+		return
+	}
+	return importer.ImportCodes(codes)
+}
+
+// 导入 codes.
+//  codes 中重复出现的 code 只导入第一次出现的, 之后出现的结果为 CodeImportStatusDuplicate, 不计入库存.
+//  如果 err != nil, report 包含了出错之前已经处理的 code 的结果, 包括已经导入但是没有核查的 code.
+func (importer *CodeImporter) ImportCodes(codes []string) (report *CodeImportReport, err error) {
+	chunkSize := importer.ChunkSize
+	if chunkSize <= 0 || chunkSize > CodeDepositSizeLimit {
+		chunkSize = CodeDepositSizeLimit
+	}
+	if chunkSize > CodeCheckSizeLimit {
+		chunkSize = CodeCheckSizeLimit
+	}
+
+	report = &CodeImportReport{
+		CardId:  importer.cardId,
+		Results: make([]CodeImportResult, 0, len(codes)),
+	}
+
+	verified := 0                             // 新导入并且核查存在(或者没有核查)的 code 数量
+	seen := make(map[string]bool, len(codes)) // 已经处理过的 code
+	for len(codes) > 0 {
+		n := chunkSize
+		if n > len(codes) {
+			n = len(codes)
+		}
+		chunk := codes[:n]
+
+		// 去掉之前的批次和本批次中已经出现过的 code
+		unique := make([]string, 0, len(chunk))
+		for _, code := range chunk {
+			if !seen[code] {
+				seen[code] = true
+				unique = append(unique, code)
+			}
+		}
+
+		status := make(map[string]string, len(unique))
+		if len(unique) > 0 {
+			var results []CodeImportResult
+			results, err = importer.importChunk(unique)
+			if err != nil && results == nil {
+				break // 这一批没有导入
+			}
+			for i := 0; i < len(results); i++ {
+				status[results[i].Code] = results[i].Status
+				switch results[i].Status {
+				case CodeImportStatusOK, CodeImportStatusUnverified:
+					verified++
+				}
+			}
+		}
+
+		for _, code := range chunk {
+			s, ok := status[code]
+			if ok {
+				delete(status, code) // 之后出现的同一个 code 为重复
+			} else {
+				s = CodeImportStatusDuplicate
+			}
+			report.Results = append(report.Results, CodeImportResult{
+				Code:   code,
+				Status: s,
+			})
+		}
+		if err != nil {
+			break // 这一批已经导入, 但是没有核查
+		}
+		codes = codes[n:]
+	}
+
+	// 即使中途出错, 已经导入成功的 code 也要增加库存
+	if importer.ModifyStock && verified > 0 {
+		if err2 := importer.wechatClient.CardModifyStock(importer.cardId, verified); err2 != nil {
+			if err == nil {
+				err = err2
+			}
+			return
+		}
+		report.IncreaseStock = verified
+	}
+	return
+}
+
+// 导入并核查一批 code, 返回的结果顺序和 chunk 一致.
+//  导入成功但是核查出错时, 返回的 results 不为 nil, 导入成功的 code 为 CodeImportStatusUnverified.
+func (importer *CodeImporter) importChunk(chunk []string) (results []CodeImportResult, err error) {
+	clt := importer.wechatClient
+
+	depositResult, err := clt.CardCodeDeposit(importer.cardId, chunk)
+	if err != nil {
+		return
+	}
+
+	status := make(map[string]string, len(chunk))
+	for _, code := range depositResult.SuccCode {
+		status[code] = CodeImportStatusOK
+	}
+	for _, code := range depositResult.DuplicateCode {
+		status[code] = CodeImportStatusDuplicate
+	}
+	for _, code := range depositResult.FailCode {
+		status[code] = CodeImportStatusFail
+	}
+
+	// 核查导入成功和重复导入的 code
+	checkCodes := make([]string, 0, len(chunk))
+	for _, code := range chunk {
+		switch status[code] {
+		case CodeImportStatusOK, CodeImportStatusDuplicate:
+			checkCodes = append(checkCodes, code)
+		}
+	}
+	if len(checkCodes) > 0 {
+		var notExistCode []string
+		if _, notExistCode, err = clt.CardCodeCheck(importer.cardId, checkCodes); err != nil {
+			for _, code := range depositResult.SuccCode {
+				status[code] = CodeImportStatusUnverified
+			}
+		}
+		for _, code := range notExistCode {
+			status[code] = CodeImportStatusNotExist
+		}
+	}
+
+	results = make([]CodeImportResult, len(chunk))
+	for i, code := range chunk {
+		s := status[code]
+		if s == "" { // 接口没有返回该 code 的结果
+			s = CodeImportStatusFail
+		}
+		results[i] = CodeImportResult{
+			Code:   code,
+			Status: s,
+		}
+	}
+	return
+}
@@ -0,0 +1,138 @@
+package card
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chanxuehong/wechat/internal/testutil"
+	"github.com/chanxuehong/wechat/mp"
+)
+
+type testTokenServer struct{}
+
+func (testTokenServer) Token() (string, error)        { return "ACCESS_TOKEN", nil }
+func (testTokenServer) TokenRefresh() (string, error) { return "ACCESS_TOKEN", nil }
+
+// 创建一个请求都转发到 handler 的 Client
+func newTestClient(handler http.Handler) (clt *Client, closeFn func()) {
+	srv := httptest.NewServer(handler)
+	return NewClient(testTokenServer{}, testutil.NewRedirectHttpClient(srv.Listener.Addr().String())), srv.Close
+}
+
+func TestCodeImporterDuplicateCodes(t *testing.T) {
+	var deposited []string
+	var increaseStock int
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/card/code/deposit", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Code []string `json:"code"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		deposited = append(deposited, req.Code...)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "succ_code": req.Code})
+	})
+	mux.HandleFunc("/card/code/checkcode", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Code []string `json:"code"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "exist_code": req.Code})
+	})
+	mux.HandleFunc("/card/modifystock", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			IncreaseStockValue int `json:"increase_stock_value"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		increaseStock += req.IncreaseStockValue
+		json.NewEncoder(w).Encode(mp.Error{ErrCode: 0})
+	})
+	clt, closeFn := newTestClient(mux)
+	defer closeFn()
+
+	importer := NewCodeImporter(clt, "pFS7Fjg8kV1IdDz01r4SQwMkuCKc")
+	importer.ChunkSize = 2 // 让重复的 code 跨越批次
+	report, err := importer.Import(strings.NewReader("a\nb\na\nc\n\nb\na\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []CodeImportResult{
+		{"a", CodeImportStatusOK},
+		{"b", CodeImportStatusOK},
+		{"a", CodeImportStatusDuplicate},
+		{"c", CodeImportStatusOK},
+		{"b", CodeImportStatusDuplicate},
+		{"a", CodeImportStatusDuplicate},
+	}
+	if len(report.Results) != len(want) {
+		t.Fatalf("Results:\nhave %+v\nwant %+v\n", report.Results, want)
+	}
+	for i := range want {
+		if report.Results[i] != want[i] {
+			t.Errorf("Results[%d]: have %+v, want %+v", i, report.Results[i], want[i])
+		}
+	}
+	if len(deposited) != 3 {
+		t.Errorf("deposited codes: have %v, want [a b c]", deposited)
+	}
+	if report.IncreaseStock != 3 || increaseStock != 3 {
+		t.Errorf("IncreaseStock: have %d (server %d), want 3", report.IncreaseStock, increaseStock)
+	}
+	if n := report.Count(CodeImportStatusOK); n != 3 {
+		t.Errorf("Count(ok): have %d, want 3", n)
+	}
+}
+
+func TestCodeImporterCheckFailed(t *testing.T) {
+	var increaseStock int
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/card/code/deposit", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Code []string `json:"code"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "succ_code": req.Code[:1], "fail_code": req.Code[1:]})
+	})
+	mux.HandleFunc("/card/code/checkcode", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(mp.Error{ErrCode: -1, ErrMsg: "system error"})
+	})
+	mux.HandleFunc("/card/modifystock", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			IncreaseStockValue int `json:"increase_stock_value"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		increaseStock += req.IncreaseStockValue
+		json.NewEncoder(w).Encode(mp.Error{ErrCode: 0})
+	})
+	clt, closeFn := newTestClient(mux)
+	defer closeFn()
+
+	importer := NewCodeImporter(clt, "pFS7Fjg8kV1IdDz01r4SQwMkuCKc")
+	importer.ChunkSize = 2
+	report, err := importer.Import(strings.NewReader("a\nb\nc\nd\n"))
+	if err == nil {
+		t.Fatal("Import: want error")
+	}
+
+	// 第一批已经导入但是没有核查, 第二批没有导入
+	want := []CodeImportResult{
+		{"a", CodeImportStatusUnverified},
+		{"b", CodeImportStatusFail},
+	}
+	if len(report.Results) != len(want) {
+		t.Fatalf("Results:\nhave %+v\nwant %+v\n", report.Results, want)
+	}
+	for i := range want {
+		if report.Results[i] != want[i] {
+			t.Errorf("Results[%d]: have %+v, want %+v", i, report.Results[i], want[i])
+		}
+	}
+	if report.IncreaseStock != 1 || increaseStock != 1 {
+		t.Errorf("IncreaseStock: have %d (server %d), want 1", report.IncreaseStock, increaseStock)
+	}
+}
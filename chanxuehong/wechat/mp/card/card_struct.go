@@ -81,6 +81,41 @@ type MemberCard struct {
 	Prerogative       string `json:"prerogative,omitempty"`       // 特权说明
 	BindOldCardURL    string `json:"bind_old_card_url,omitempty"` // 可选; 绑定旧卡的url，与“activate_url”字段二选一必填。
 	ActivateURL       string `json:"activate_url,omitempty"`      // 可选; 激活会员卡的url，与“bind_old_card_url”字段二选一必填。
+
+	BackgroundPicURL string `json:"background_pic_url,omitempty"` // 可选; 商家自定义会员卡背景图
+	AutoActivate     *bool  `json:"auto_activate,omitempty"`      // 可选; 设置为true时用户领取会员卡后系统自动将其激活，无需调用激活接口
+	WxActivate       *bool  `json:"wx_activate,omitempty"`        // 可选; 设置为true时会员卡支持一键开卡，需调用开卡组件设置接口设置开卡表单
+
+	CustomField1 *MemberCardCustomField `json:"custom_field1,omitempty"` // 可选; 自定义会员信息类目，会员卡激活后显示
+	CustomField2 *MemberCardCustomField `json:"custom_field2,omitempty"` // 可选; 自定义会员信息类目，会员卡激活后显示
+	CustomField3 *MemberCardCustomField `json:"custom_field3,omitempty"` // 可选; 自定义会员信息类目，会员卡激活后显示
+	CustomCell1  *MemberCardCustomCell  `json:"custom_cell1,omitempty"`  // 可选; 自定义会员信息类目，会员卡激活后显示
+}
+
+const (
+	// 会员卡自定义信息类目的名称类型
+	FieldNameTypeLevel       = "FIELD_NAME_TYPE_LEVEL"      // 等级
+	FieldNameTypeCoupon      = "FIELD_NAME_TYPE_COUPON"     // 优惠券
+	FieldNameTypeStamp       = "FIELD_NAME_TYPE_STAMP"      // 印花
+	FieldNameTypeDiscount    = "FIELD_NAME_TYPE_DISCOUNT"   // 折扣
+	FieldNameTypeAchievement = "FIELD_NAME_TYPE_ACHIEVEMEN" // 成就
+	FieldNameTypeMileage     = "FIELD_NAME_TYPE_MILEAGE"    // 里程
+	FieldNameTypeSetPoints   = "FIELD_NAME_TYPE_SET_POINTS" // 集点
+	FieldNameTypeTimes       = "FIELD_NAME_TYPE_TIMS"       // 次数
+)
+
+// 会员卡自定义信息类目, 即 custom_field1, custom_field2, custom_field3
+type MemberCardCustomField struct {
+	NameType string `json:"name_type,omitempty"` // 会员信息类目名称类型, 与 name 二选一
+	Name     string `json:"name,omitempty"`      // 会员信息类目自定义名称，当开发者变更这类类目信息的value值时，不会触发系统模板消息通知用户
+	URL      string `json:"url,omitempty"`       // 可选; 点击类目跳转外链url
+}
+
+// 会员卡自定义入口, 即 custom_cell1
+type MemberCardCustomCell struct {
+	Name string `json:"name,omitempty"` // 入口名称
+	Tips string `json:"tips,omitempty"` // 入口右侧提示语，6个汉字内
+	URL  string `json:"url,omitempty"`  // 入口跳转链接
 }
 
 // 景点门票
@@ -172,6 +207,15 @@ type CardBaseInfo struct {
 	Source         string  `json:"source,omitempty"`           // 可选; 第三方来源名，如携程
 	URLNameType    string  `json:"url_name_type,omitempty"`    // 可选; 商户自定义cell 名称， 与custom_url 字段共同使用
 	CustomURL      string  `json:"custom_url,omitempty"`       // 可选; 商户自定义cell 跳转外链的地址链接,跳转页面内容需与自定义cell 名称保持一致。
+
+	CustomURLName        string `json:"custom_url_name,omitempty"`         // 可选; 自定义跳转外链的入口名字
+	CustomURLSubTitle    string `json:"custom_url_sub_title,omitempty"`    // 可选; 显示在入口右侧的提示语
+	PromotionURLName     string `json:"promotion_url_name,omitempty"`      // 可选; 营销场景的自定义入口名称
+	PromotionURL         string `json:"promotion_url,omitempty"`           // 可选; 入口跳转外链的地址链接
+	PromotionURLSubTitle string `json:"promotion_url_sub_title,omitempty"` // 可选; 显示在营销入口右侧的提示语
+	CenterTitle          string `json:"center_title,omitempty"`            // 可选; 卡券顶部居中的按钮，仅在卡券状态正常(可以核销)时显示
+	CenterSubTitle       string `json:"center_sub_title,omitempty"`        // 可选; 显示在入口下方的提示语，仅在卡券状态正常(可以核销)时显示
+	CenterURL            string `json:"center_url,omitempty"`              // 可选; 顶部居中的url，仅在卡券状态正常(可以核销)时显示
 }
 
 type DateInfo struct {
@@ -0,0 +1,69 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     gaowenbin(gaowenbinmarr@gmail.com), chanxuehong(chanxuehong@gmail.com)
+
+package card
+
+import (
+	"errors"
+
+	"github.com/chanxuehong/wechat/mp"
+)
+
+// 设置买单接口.
+//  创建卡券之后，开发者可以通过设置微信买单接口设置该card_id支持微信买单功能。
+//  设置买单的card_id必须已经配置了门店，否则会报错。
+//  cardId: 卡券ID
+//  isOpen: 是否开启买单功能
+func (clt *Client) PayCellSet(cardId string, isOpen bool) (err error) {
+	var request = struct {
+		CardId string `json:"card_id"`
+		IsOpen bool   `json:"is_open"`
+	}{
+		CardId: cardId,
+		IsOpen: isOpen,
+	}
+
+	var result mp.Error
+
+	incompleteURL := "https://api.weixin.qq.com/card/paycell/set?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
+
+type SelfConsumeCellSetParameters struct {
+	CardId           string `json:"card_id"`                      // 卡券ID
+	IsOpen           bool   `json:"is_open"`                      // 是否开启自助核销功能
+	NeedVerifyCode   *bool  `json:"need_verify_cod,omitempty"`    // 可选; 用户核销时是否需要输入验证码，默认为false
+	NeedRemarkAmount *bool  `json:"need_remark_amount,omitempty"` // 可选; 用户核销时是否需要备注核销金额，默认为false
+}
+
+// 设置自助核销接口.
+//  创建卡券之后，开发者可以通过设置微信买单接口设置该card_id支持自助核销功能。
+//  设置自助核销的card_id必须已经配置了门店，否则会报错。
+func (clt *Client) SelfConsumeCellSet(para *SelfConsumeCellSetParameters) (err error) {
+	if para == nil {
+		return errors.New("nil SelfConsumeCellSetParameters")
+	}
+
+	var result mp.Error
+
+	incompleteURL := "https://api.weixin.qq.com/card/selfconsumecell/set?access_token="
+	if err = clt.PostJSON(incompleteURL, para, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
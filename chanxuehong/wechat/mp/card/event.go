@@ -15,6 +15,8 @@ const (
 	EventTypeUserGetCard      = "user_get_card"       // 领取卡券事件
 	EventTypeUserDelCard      = "user_del_card"       // 删除卡券事件
 
+	EventTypeSubmitMemberCardUserInfo = "submit_membercard_user_info" // 会员卡激活事件
+	EventTypeUpdateMemberCard         = "update_member_card"          // 会员卡内容更新事件
 )
 
 // 卡券通过审核，微信会把这个事件推送到开发者填写的URL
@@ -94,3 +96,45 @@ func GetUserDelCardEvent(msg *mp.MixedMessage) *UserDelCardEvent {
 		UserCardCode:        msg.UserCardCode,
 	}
 }
+
+// 用户通过一键激活的方式提交信息并点击激活或者用户修改会员卡信息后，微信会把这个事件推送到开发者填写的URL。
+type SubmitMemberCardUserInfoEvent struct {
+	XMLName struct{} `xml:"xml" json:"-"`
+	mp.CommonMessageHeader
+
+	Event        string `xml:"Event"        json:"Event"`        // 事件类型, submit_membercard_user_info
+	CardId       string `xml:"CardId"       json:"CardId"`       // 卡券ID
+	UserCardCode string `xml:"UserCardCode" json:"UserCardCode"` // 卡券Code码
+}
+
+func GetSubmitMemberCardUserInfoEvent(msg *mp.MixedMessage) *SubmitMemberCardUserInfoEvent {
+	return &SubmitMemberCardUserInfoEvent{
+		CommonMessageHeader: msg.CommonMessageHeader,
+		Event:               msg.Event,
+		CardId:              msg.CardId,
+		UserCardCode:        msg.UserCardCode,
+	}
+}
+
+// 当用户的会员卡积分余额发生变动时，微信会把这个事件推送到开发者填写的URL。
+type UpdateMemberCardEvent struct {
+	XMLName struct{} `xml:"xml" json:"-"`
+	mp.CommonMessageHeader
+
+	Event         string `xml:"Event"         json:"Event"`         // 事件类型, update_member_card
+	CardId        string `xml:"CardId"        json:"CardId"`        // 卡券ID
+	UserCardCode  string `xml:"UserCardCode"  json:"UserCardCode"`  // 卡券Code码
+	ModifyBonus   int    `xml:"ModifyBonus"   json:"ModifyBonus"`   // 变动的积分值
+	ModifyBalance int    `xml:"ModifyBalance" json:"ModifyBalance"` // 变动的余额值
+}
+
+func GetUpdateMemberCardEvent(msg *mp.MixedMessage) *UpdateMemberCardEvent {
+	return &UpdateMemberCardEvent{
+		CommonMessageHeader: msg.CommonMessageHeader,
+		Event:               msg.Event,
+		CardId:              msg.CardId,
+		UserCardCode:        msg.UserCardCode,
+		ModifyBonus:         msg.ModifyBonus,
+		ModifyBalance:       msg.ModifyBalance,
+	}
+}
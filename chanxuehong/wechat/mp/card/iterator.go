@@ -0,0 +1,223 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     gaowenbin(gaowenbinmarr@gmail.com), chanxuehong(chanxuehong@gmail.com)
+
+package card
+
+import (
+	"fmt"
+	"sync"
+)
+
+// 卡券遍历器
+//
+//  iter, err := Client.CardIterator([]string{card.CardStatusVerifyOK}, card.CardPageSizeLimit)
+//  if err != nil {
+//      // TODO: 增加你的代码
+//  }
+//
+//  for iter.HasNext() {
+//      cardIds, err := iter.NextPage()
+//      if err != nil {
+//          // TODO: 增加你的代码
+//      }
+//      // TODO: 增加你的代码
+//  }
+type CardIterator struct {
+	statusList []string // 筛选的卡券状态
+	pageSize   int      // 每页的卡券数量
+
+	lastOffset     int      // 最近一次获取的数据的偏移量
+	lastCardIdList []string // 最近一次获取的卡券ID 列表
+	totalNum       int      // 最近一次获取的卡券ID 总数
+
+	wechatClient   *Client // 关联的微信 Client
+	nextPageCalled bool    // NextPage() 是否调用过
+}
+
+func (iter *CardIterator) Total() int {
+	return iter.totalNum
+}
+
+func (iter *CardIterator) HasNext() bool {
+	if !iter.nextPageCalled { // 还没有调用 NextPage(), 从创建的时候获取的数据来判断
+		return len(iter.lastCardIdList) > 0
+	}
+
+	// 已经调用过 NextPage(), 上一页满页并且没有达到总数才可能还有数据
+	return len(iter.lastCardIdList) == iter.pageSize &&
+		iter.lastOffset+len(iter.lastCardIdList) < iter.totalNum
+}
+
+func (iter *CardIterator) NextPage() (cardIdList []string, err error) {
+	if !iter.nextPageCalled { // 还没有调用 NextPage(), 从创建的时候获取的数据中获取
+		cardIdList = iter.lastCardIdList
+		iter.nextPageCalled = true
+		return
+	}
+
+	// 不是第一次调用的都要从服务器拉取数据
+	offset := iter.lastOffset + len(iter.lastCardIdList)
+	cardIdList, totalNum, err := iter.wechatClient.CardBatchGetByStatus(offset, iter.pageSize, iter.statusList)
+	if err != nil {
+		return
+	}
+
+	iter.lastOffset = offset
+	iter.lastCardIdList = cardIdList
+	iter.totalNum = totalNum
+	return
+}
+
+// 获取下一页卡券的详情, concurrency 是并发调用 CardGet 的数量.
+//  返回的 cards 的顺序和卡券ID 列表的顺序一致.
+func (iter *CardIterator) NextPageDetail(concurrency int) (cards []*Card, err error) {
+	cardIdList, err := iter.NextPage()
+	if err != nil {
+		return
+	}
+	return iter.wechatClient.CardGetMulti(cardIdList, concurrency)
+}
+
+// 获取卡券遍历器.
+//  statusList: 可选; 拉出指定状态的卡券列表, 为空时拉出所有状态的卡券
+//  pageSize:   每页的卡券数量, 最大为 CardPageSizeLimit
+func (clt *Client) CardIterator(statusList []string, pageSize int) (iter *CardIterator, err error) {
+	if pageSize <= 0 || pageSize > CardPageSizeLimit {
+		err = fmt.Errorf("invalid pageSize: %d", pageSize)
+		return
+	}
+
+	cardIdList, totalNum, err := clt.CardBatchGetByStatus(0, pageSize, statusList)
+	if err != nil {
+		return
+	}
+
+	iter = &CardIterator{
+		statusList:     statusList,
+		pageSize:       pageSize,
+		lastOffset:     0,
+		lastCardIdList: cardIdList,
+		totalNum:       totalNum,
+		wechatClient:   clt,
+		nextPageCalled: false,
+	}
+	return
+}
+
+// 并发查询多个卡券的详情.
+//  concurrency 是并发调用 CardGet 的数量, 小于等于 0 时为 1;
+//  返回的 cards 的顺序和 cardIdList 的顺序一致, 如果有错误, 返回第一个出错的卡券的错误.
+func (clt *Client) CardGetMulti(cardIdList []string, concurrency int) (cards []*Card, err error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if concurrency > len(cardIdList) {
+		concurrency = len(cardIdList)
+	}
+
+	cards = make([]*Card, len(cardIdList))
+	errs := make([]error, len(cardIdList))
+
+	indexChan := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for index := range indexChan {
+				cards[index], errs[index] = clt.CardGet(cardIdList[index])
+			}
+		}()
+	}
+	for i := 0; i < len(cardIdList); i++ {
+		indexChan <- i
+	}
+	close(indexChan)
+	wg.Wait()
+
+	for i := 0; i < len(errs); i++ {
+		if errs[i] != nil {
+			cards = nil
+			err = fmt.Errorf("get card %s: %s", cardIdList[i], errs[i])
+			return
+		}
+	}
+	return
+}
+
+// 门店遍历器
+//
+//  iter, err := Client.LocationIterator(50)
+//  if err != nil {
+//      // TODO: 增加你的代码
+//  }
+//
+//  for iter.HasNext() {
+//      locations, err := iter.NextPage()
+//      if err != nil {
+//          // TODO: 增加你的代码
+//      }
+//      // TODO: 增加你的代码
+//  }
+type LocationIterator struct {
+	pageSize int // 每页的门店数量
+
+	lastOffset       int        // 最近一次获取的数据的偏移量
+	lastLocationList []Location // 最近一次获取的门店列表
+
+	wechatClient   *Client // 关联的微信 Client
+	nextPageCalled bool    // NextPage() 是否调用过
+}
+
+func (iter *LocationIterator) HasNext() bool {
+	if !iter.nextPageCalled { // 还没有调用 NextPage(), 从创建的时候获取的数据来判断
+		return len(iter.lastLocationList) > 0
+	}
+
+	// 如果上一次读取的数据等于 pageSize, 则"可能"还有数据; 否则肯定是没有数据了.
+	return len(iter.lastLocationList) == iter.pageSize
+}
+
+func (iter *LocationIterator) NextPage() (LocationList []Location, err error) {
+	if !iter.nextPageCalled { // 还没有调用 NextPage(), 从创建的时候获取的数据中获取
+		LocationList = iter.lastLocationList
+		iter.nextPageCalled = true
+		return
+	}
+
+	// 不是第一次调用的都要从服务器拉取数据
+	offset := iter.lastOffset + len(iter.lastLocationList)
+	LocationList, err = iter.wechatClient.LocationBatchGet(offset, iter.pageSize)
+	if err != nil {
+		return
+	}
+
+	iter.lastOffset = offset
+	iter.lastLocationList = LocationList
+	return
+}
+
+// 获取门店遍历器, pageSize 是每页的门店数量, 必须大于 0.
+func (clt *Client) LocationIterator(pageSize int) (iter *LocationIterator, err error) {
+	// LocationBatchGet 的 count 为 0 时默认拉取全部门店, 所以这里不允许 pageSize == 0
+	if pageSize <= 0 {
+		err = fmt.Errorf("invalid pageSize: %d", pageSize)
+		return
+	}
+
+	LocationList, err := clt.LocationBatchGet(0, pageSize)
+	if err != nil {
+		return
+	}
+
+	iter = &LocationIterator{
+		pageSize:         pageSize,
+		lastOffset:       0,
+		lastLocationList: LocationList,
+		wechatClient:     clt,
+		nextPageCalled:   false,
+	}
+	return
+}
@@ -0,0 +1,67 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     gaowenbin(gaowenbinmarr@gmail.com), chanxuehong(chanxuehong@gmail.com)
+
+package card
+
+import (
+	"errors"
+
+	"github.com/chanxuehong/wechat/mp"
+)
+
+const (
+	// 货架投放页面的场景值
+	LandingPageSceneNearBy         = "SCENE_NEAR_BY"          // 附近
+	LandingPageSceneMenu           = "SCENE_MENU"             // 自定义菜单
+	LandingPageSceneQRCode         = "SCENE_QRCODE"           // 二维码
+	LandingPageSceneArticle        = "SCENE_ARTICLE"          // 公众号文章
+	LandingPageSceneH5             = "SCENE_H5"               // h5页面
+	LandingPageSceneIVR            = "SCENE_IVR"              // 自动回复
+	LandingPageSceneCardCustomCell = "SCENE_CARD_CUSTOM_CELL" // 卡券自定义cell
+)
+
+// 货架中的一个卡券
+type LandingPageCard struct {
+	CardId   string `json:"card_id"`   // 所要在页面投放的card_id
+	ThumbURL string `json:"thumb_url"` // 缩略图url
+}
+
+type LandingPageCreateParameters struct {
+	Banner    string            `json:"banner"`     // 页面的banner图片链接，须调用上传图片接口上传图片获得链接
+	PageTitle string            `json:"page_title"` // 页面的title
+	CanShare  bool              `json:"can_share"`  // 页面是否可以分享
+	Scene     string            `json:"scene"`      // 投放页面的场景值, LandingPageSceneXXX
+	CardList  []LandingPageCard `json:"card_list"`  // 卡券列表
+}
+
+// 创建货架接口.
+//  开发者需调用该接口创建货架链接，用于卡券投放。创建货架时需填写投放路径的场景字段。
+//  url:    货架链接
+//  pageId: 货架ID, 货架的唯一标识
+func (clt *Client) LandingPageCreate(para *LandingPageCreateParameters) (url string, pageId int64, err error) {
+	if para == nil {
+		err = errors.New("nil LandingPageCreateParameters")
+		return
+	}
+
+	var result struct {
+		mp.Error
+		URL    string `json:"url"`
+		PageId int64  `json:"page_id"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/card/landingpage/create?access_token="
+	if err = clt.PostJSON(incompleteURL, para, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	url = result.URL
+	pageId = result.PageId
+	return
+}
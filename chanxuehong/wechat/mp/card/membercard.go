@@ -51,6 +51,24 @@ type MemberCardUpdateUserParameters struct {
 	RecordBonus   string `json:"record_bonus,omitempty"`   // 商家自定义积分消耗记录，不超过14 个汉字
 	AddBalance    int    `json:"add_balance,omitempty"`    // 需要变更的余额，扣除金额用“-”表示。单位为分
 	RecordBalance string `json:"record_balance,omitempty"` // 商家自定义金额消耗记录，不超过14 个汉字
+
+	Bonus   *int `json:"bonus,omitempty"`   // 可选; 需要设置的积分全量值，传入的数值会直接显示，与 add_bonus 二选一
+	Balance *int `json:"balance,omitempty"` // 可选; 需要设置的余额全量值，传入的数值会直接显示，与 add_balance 二选一
+
+	CustomFieldValue1 string `json:"custom_field_value1,omitempty"` // 可选; 创建时字段custom_field1定义类型的最新数值，限制为4个汉字，12字节
+	CustomFieldValue2 string `json:"custom_field_value2,omitempty"` // 可选; 创建时字段custom_field2定义类型的最新数值，限制为4个汉字，12字节
+	CustomFieldValue3 string `json:"custom_field_value3,omitempty"` // 可选; 创建时字段custom_field3定义类型的最新数值，限制为4个汉字，12字节
+
+	NotifyOptional *MemberCardNotifyOptional `json:"notify_optional,omitempty"` // 可选; 控制原生消息结构体，包含各字段的消息控制字段
+}
+
+// 会员卡交易时的消息提醒控制
+type MemberCardNotifyOptional struct {
+	IsNotifyBonus        *bool `json:"is_notify_bonus,omitempty"`         // 积分变动时是否触发系统模板消息，默认为true
+	IsNotifyBalance      *bool `json:"is_notify_balance,omitempty"`       // 余额变动时是否触发系统模板消息，默认为true
+	IsNotifyCustomField1 *bool `json:"is_notify_custom_field1,omitempty"` // 自定义group1变动时是否触发系统模板消息，默认为false
+	IsNotifyCustomField2 *bool `json:"is_notify_custom_field2,omitempty"` // 自定义group2变动时是否触发系统模板消息，默认为false
+	IsNotifyCustomField3 *bool `json:"is_notify_custom_field3,omitempty"` // 自定义group3变动时是否触发系统模板消息，默认为false
 }
 
 type MemberCardUpdateUserResult struct {
@@ -84,3 +102,69 @@ func (clt *Client) MemberCardUpdateUser(para *MemberCardUpdateUserParameters) (r
 	rst = &result.MemberCardUpdateUserResult
 	return
 }
+
+// 会员信息的一个字段
+type MemberCardFormField struct {
+	Name      string   `json:"name"`                 // 字段名称, 对于通用字段是 USER_FORM_INFO_FLAG_XXX
+	Value     string   `json:"value,omitempty"`      // 字段的值
+	ValueList []string `json:"value_list,omitempty"` // 多选字段的值列表
+}
+
+// 用户填写的会员信息
+type MemberCardUserFormInfo struct {
+	CommonFieldList []MemberCardFormField `json:"common_field_list,omitempty"` // 微信格式化的选项类型
+	CustomFieldList []MemberCardFormField `json:"custom_field_list,omitempty"` // 自定义选项类型
+}
+
+const (
+	// 会员卡的状态
+	UserCardStatusNormal      = "NORMAL"       // 正常
+	UserCardStatusExpire      = "EXPIRE"       // 已过期
+	UserCardStatusGifting     = "GIFTING"      // 转赠中
+	UserCardStatusGiftSucc    = "GIFT_SUCC"    // 转赠成功
+	UserCardStatusGiftTimeout = "GIFT_TIMEOUT" // 转赠超时
+	UserCardStatusDelete      = "DELETE"       // 已删除
+	UserCardStatusUnavailable = "UNAVAILABLE"  // 已失效
+)
+
+type MemberCardUserInfo struct {
+	OpenId           string                 `json:"openid"`            // 用户在本公众号内唯一识别码
+	Nickname         string                 `json:"nickname"`          // 用户昵称
+	MembershipNumber string                 `json:"membership_number"` // 会员卡编号
+	Bonus            int                    `json:"bonus"`             // 积分信息
+	Balance          int                    `json:"balance"`           // 余额信息
+	Sex              string                 `json:"sex"`               // 用户性别
+	UserInfo         MemberCardUserFormInfo `json:"user_info"`         // 会员信息
+	UserCardStatus   string                 `json:"user_card_status"`  // 当前用户的会员卡状态
+	HasActive        bool                   `json:"has_active"`        // 该卡是否已经被激活
+}
+
+// 拉取会员信息.
+//  cardId: 查询会员卡的cardid
+//  code:   所查询用户领取到的code值
+func (clt *Client) MemberCardUserInfoGet(cardId, code string) (info *MemberCardUserInfo, err error) {
+	var request = struct {
+		CardId string `json:"card_id"`
+		Code   string `json:"code"`
+	}{
+		CardId: cardId,
+		Code:   code,
+	}
+
+	var result struct {
+		mp.Error
+		MemberCardUserInfo
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/card/membercard/userinfo/get?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	info = &result.MemberCardUserInfo
+	return
+}
@@ -0,0 +1,113 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     gaowenbin(gaowenbinmarr@gmail.com), chanxuehong(chanxuehong@gmail.com)
+
+package card
+
+import (
+	"errors"
+
+	"github.com/chanxuehong/wechat/mp"
+)
+
+const (
+	// 开卡表单的通用字段
+	UserFormInfoFlagMobile             = "USER_FORM_INFO_FLAG_MOBILE"            // 手机号
+	UserFormInfoFlagSex                = "USER_FORM_INFO_FLAG_SEX"               // 性别
+	UserFormInfoFlagName               = "USER_FORM_INFO_FLAG_NAME"              // 姓名
+	UserFormInfoFlagBirthday           = "USER_FORM_INFO_FLAG_BIRTHDAY"          // 生日
+	UserFormInfoFlagIdCard             = "USER_FORM_INFO_FLAG_IDCARD"            // 身份证
+	UserFormInfoFlagEmail              = "USER_FORM_INFO_FLAG_EMAIL"             // 邮箱
+	UserFormInfoFlagLocation           = "USER_FORM_INFO_FLAG_LOCATION"          // 详细地址
+	UserFormInfoFlagEducationBackgroud = "USER_FORM_INFO_FLAG_EDUCATION_BACKGRO" // 教育背景
+	UserFormInfoFlagIndustry           = "USER_FORM_INFO_FLAG_INDUSTRY"          // 行业
+	UserFormInfoFlagIncome             = "USER_FORM_INFO_FLAG_INCOME"            // 收入
+	UserFormInfoFlagHabit              = "USER_FORM_INFO_FLAG_HABIT"             // 兴趣爱好
+)
+
+const (
+	// 开卡表单的富文本字段类型
+	FormFieldRadio    = "FORM_FIELD_RADIO"     // 自定义单选
+	FormFieldSelect   = "FORM_FIELD_SELECT"    // 自定义选择项
+	FormFieldCheckBox = "FORM_FIELD_CHECK_BOX" // 自定义多选
+)
+
+// 开卡表单的富文本字段
+type ActivateUserFormRichField struct {
+	Type   string   `json:"type"`   // 富文本类型, FORM_FIELD_XXX
+	Name   string   `json:"name"`   // 字段名
+	Values []string `json:"values"` // 选择项
+}
+
+// 开卡表单
+type ActivateUserForm struct {
+	CanModify         *bool                       `json:"can_modify,omitempty"`           // 可选; 当前结构（required_form或者optional_form ）内的字段是否允许用户激活后再次修改
+	CommonFieldIdList []string                    `json:"common_field_id_list,omitempty"` // 可选; 微信格式化的选项类型, USER_FORM_INFO_FLAG_XXX
+	CustomFieldList   []string                    `json:"custom_field_list,omitempty"`    // 可选; 自定义选项名称
+	RichFieldList     []ActivateUserFormRichField `json:"rich_field_list,omitempty"`      // 可选; 自定义富文本类型
+}
+
+// 开卡表单的外链
+type ActivateUserFormLink struct {
+	Name string `json:"name"` // 链接名称
+	URL  string `json:"url"`  // 自定义url，请填写http:// 或者https://开头的链接
+}
+
+type ActivateUserFormSetParameters struct {
+	CardId string `json:"card_id"` // 卡券ID
+
+	RequiredForm *ActivateUserForm `json:"required_form,omitempty"` // 可选; 会员卡激活时的必填选项
+	OptionalForm *ActivateUserForm `json:"optional_form,omitempty"` // 可选; 会员卡激活时的选填项
+
+	ServiceStatement *ActivateUserFormLink `json:"service_statement,omitempty"` // 可选; 服务声明，用于放置商户会员卡守则
+	BindOldCard      *ActivateUserFormLink `json:"bind_old_card,omitempty"`     // 可选; 绑定老会员链接
+}
+
+// 设置开卡字段.
+//  会员卡创建时 wx_activate 填写 true 后, 需调用该接口设置用户激活时需要填写的选项.
+func (clt *Client) ActivateUserFormSet(para *ActivateUserFormSetParameters) (err error) {
+	if para == nil {
+		return errors.New("nil ActivateUserFormSetParameters")
+	}
+
+	var result mp.Error
+
+	incompleteURL := "https://api.weixin.qq.com/card/membercard/activateuserform/set?access_token="
+	if err = clt.PostJSON(incompleteURL, para, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
+
+// 获取用户提交的开卡资料.
+//  activateTicket: 用户填写开卡资料后跳转到激活页面时 url 上带的 activate_ticket 参数
+func (clt *Client) ActivateTempInfoGet(activateTicket string) (info *MemberCardUserFormInfo, err error) {
+	var request = struct {
+		ActivateTicket string `json:"activate_ticket"`
+	}{
+		ActivateTicket: activateTicket,
+	}
+
+	var result struct {
+		mp.Error
+		Info MemberCardUserFormInfo `json:"info"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/card/membercard/activatetempinfo/get?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	info = &result.Info
+	return
+}
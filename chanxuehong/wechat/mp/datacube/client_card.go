@@ -0,0 +1,155 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package datacube
+
+import (
+	"errors"
+	"time"
+
+	"github.com/chanxuehong/wechat/mp"
+)
+
+const (
+	// 卡券数据的来源
+	CondSourceMP  = 0 // 公众平台创建的卡券数据
+	CondSourceAPI = 1 // API 创建的卡券数据
+)
+
+// 获取卡券统计数据的请求结构.
+//  卡券统计数据查询时间区间需<=62天, 且只支持查询 2015-07-01 之后的数据.
+type CardRequest struct {
+	Request
+	CondSource int    `json:"cond_source"`       // 卡券来源, CondSourceMP 或 CondSourceAPI
+	CardId     string `json:"card_id,omitempty"` // 可选; 卡券ID, 只对 GetCardCardInfo 有效, 填写后指定拉出该卡券的相关数据
+}
+
+// NewCardRequest 创建一个 CardRequest.
+//  请注意 BeginDate, EndDate 的 Location.
+func NewCardRequest(BeginDate, EndDate time.Time, CondSource int) *CardRequest {
+	return &CardRequest{
+		Request:    *NewRequest(BeginDate, EndDate),
+		CondSource: CondSource,
+	}
+}
+
+type CardBaseData struct {
+	ViewCount    int `json:"view_cnt"`     // 浏览次数
+	ViewUser     int `json:"view_user"`    // 浏览人数
+	ReceiveCount int `json:"receive_cnt"`  // 领取次数
+	ReceiveUser  int `json:"receive_user"` // 领取人数
+	VerifyCount  int `json:"verify_cnt"`   // 使用次数
+	VerifyUser   int `json:"verify_user"`  // 使用人数
+	GivenCount   int `json:"given_cnt"`    // 转赠次数
+	GivenUser    int `json:"given_user"`   // 转赠人数
+	ExpireCount  int `json:"expire_cnt"`   // 过期次数
+	ExpireUser   int `json:"expire_user"`  // 过期人数
+}
+
+// 卡券概况数据
+type CardBizUinData struct {
+	RefDate string `json:"ref_date"` // 数据的日期, YYYY-MM-DD 格式
+	CardBaseData
+}
+
+// 拉取卡券概况数据.
+//  支持调用该接口拉取本商户的总体数据情况，包括时间区间内的各指标总量。
+func (clt *Client) GetCardBizUinInfo(req *CardRequest) (list []CardBizUinData, err error) {
+	if req == nil {
+		err = errors.New("nil CardRequest")
+		return
+	}
+
+	var result struct {
+		mp.Error
+		List []CardBizUinData `json:"list"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/datacube/getcardbizuininfo?access_token="
+	if err = clt.PostJSON(incompleteURL, req, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	list = result.List
+	return
+}
+
+// 免费券数据
+type CardCardData struct {
+	RefDate  string `json:"ref_date"`  // 数据的日期, YYYY-MM-DD 格式
+	CardId   string `json:"card_id"`   // 卡券ID
+	CardType int    `json:"card_type"` // 卡券类型
+	CardBaseData
+}
+
+// 获取免费券数据.
+//  支持开发者调用该接口拉取免费券（优惠券、团购券、折扣券、礼品券）在固定时间区间内的相关数据。
+func (clt *Client) GetCardCardInfo(req *CardRequest) (list []CardCardData, err error) {
+	if req == nil {
+		err = errors.New("nil CardRequest")
+		return
+	}
+
+	var result struct {
+		mp.Error
+		List []CardCardData `json:"list"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/datacube/getcardcardinfo?access_token="
+	if err = clt.PostJSON(incompleteURL, req, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	list = result.List
+	return
+}
+
+// 会员卡概况数据
+type CardMemberCardData struct {
+	RefDate          string `json:"ref_date"`           // 数据的日期, YYYY-MM-DD 格式
+	ViewCount        int    `json:"view_cnt"`           // 浏览次数
+	ViewUser         int    `json:"view_user"`          // 浏览人数
+	ReceiveCount     int    `json:"receive_cnt"`        // 领取次数
+	ReceiveUser      int    `json:"receive_user"`       // 领取人数
+	ActiveUser       int    `json:"active_user"`        // 激活人数
+	VerifyCount      int    `json:"verify_cnt"`         // 使用次数
+	VerifyUser       int    `json:"verify_user"`        // 使用人数
+	TotalUser        int    `json:"total_user"`         // 有效会员总人数
+	TotalReceiveUser int    `json:"total_receive_user"` // 历史领取会员卡总人数
+}
+
+// 拉取会员卡数据.
+//  支持开发者调用该接口拉取公众平台创建的会员卡相关数据。
+func (clt *Client) GetCardMemberCardInfo(req *CardRequest) (list []CardMemberCardData, err error) {
+	if req == nil {
+		err = errors.New("nil CardRequest")
+		return
+	}
+
+	var result struct {
+		mp.Error
+		List []CardMemberCardData `json:"list"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/datacube/getcardmembercardinfo?access_token="
+	if err = clt.PostJSON(incompleteURL, req, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	list = result.List
+	return
+}
@@ -0,0 +1,159 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package dkf
+
+import (
+	"net/url"
+
+	"github.com/chanxuehong/wechat/mp"
+)
+
+// 创建会话.
+//  开发者可以使用本接口，为多客服的客服工号创建会话，将某个客户直接指定给客服工号接待，
+//  需要注意此接口不会受客服自动接入数以及自动接入开关限制。只能为在线的客服（PC客户端在线，或者已绑定多客服助手）创建会话。
+//  kfAccount: 完整客服账号，格式为：账号前缀@公众号微信号
+//  openId:    客户openid
+//  text:      附加信息，文本会展示在客服人员的多客服客户端, 可以为空
+func (clt *Client) CreateSession(kfAccount, openId, text string) (err error) {
+	var request = struct {
+		KfAccount string `json:"kf_account"`
+		OpenId    string `json:"openid"`
+		Text      string `json:"text,omitempty"`
+	}{
+		KfAccount: kfAccount,
+		OpenId:    openId,
+		Text:      text,
+	}
+
+	var result mp.Error
+
+	incompleteURL := "https://api.weixin.qq.com/customservice/kfsession/create?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
+
+// 关闭会话.
+//  kfAccount: 完整客服账号，格式为：账号前缀@公众号微信号
+//  openId:    客户openid
+//  text:      附加信息，文本会展示在客服人员的多客服客户端, 可以为空
+func (clt *Client) CloseSession(kfAccount, openId, text string) (err error) {
+	var request = struct {
+		KfAccount string `json:"kf_account"`
+		OpenId    string `json:"openid"`
+		Text      string `json:"text,omitempty"`
+	}{
+		KfAccount: kfAccount,
+		OpenId:    openId,
+		Text:      text,
+	}
+
+	var result mp.Error
+
+	incompleteURL := "https://api.weixin.qq.com/customservice/kfsession/close?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
+
+// 客户的会话状态
+type Session struct {
+	KfAccount  string `json:"kf_account"` // 正在接待的客服，为空表示没有人在接待
+	CreateTime int64  `json:"createtime"` // 会话接入的时间
+}
+
+// 获取客户的会话状态.
+//  openId: 客户openid
+func (clt *Client) GetSession(openId string) (session *Session, err error) {
+	var result struct {
+		mp.Error
+		Session
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/customservice/kfsession/getsession?openid=" +
+		url.QueryEscape(openId) + "&access_token="
+	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	session = &result.Session
+	return
+}
+
+// 客服的一个会话
+type KfSession struct {
+	OpenId     string `json:"openid"`     // 客户openid
+	CreateTime int64  `json:"createtime"` // 会话创建时间，UNIX时间戳
+}
+
+// 获取客服的会话列表.
+//  kfAccount: 完整客服账号，格式为：账号前缀@公众号微信号
+func (clt *Client) GetSessionList(kfAccount string) (sessionList []KfSession, err error) {
+	var result struct {
+		mp.Error
+		SessionList []KfSession `json:"sessionlist"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/customservice/kfsession/getsessionlist?kf_account=" +
+		url.QueryEscape(kfAccount) + "&access_token="
+	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	sessionList = result.SessionList
+	return
+}
+
+// 一个未接入会话
+type WaitCase struct {
+	OpenId     string `json:"openid"`               // 客户openid
+	KfAccount  string `json:"kf_account,omitempty"` // 指定接待的客服，为空表示未指定客服
+	CreateTime int64  `json:"createtime"`           // 用户来访时间，UNIX时间戳
+	LatestTime int64  `json:"latest_time"`          // 粉丝的最后一条消息的时间，UNIX时间戳
+}
+
+// 获取未接入会话列表.
+//  count: 未接入会话数量, 可能大于 len(waitCaseList), 该接口最多返回最早进入队列的 100 个未接入会话
+func (clt *Client) GetWaitCase() (count int, waitCaseList []WaitCase, err error) {
+	var result struct {
+		mp.Error
+		Count        int        `json:"count"`
+		WaitCaseList []WaitCase `json:"waitcaselist"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/customservice/kfsession/getwaitcase?access_token="
+	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	count = result.Count
+	waitCaseList = result.WaitCaseList
+	return
+}
@@ -0,0 +1,69 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package dkf
+
+import (
+	"github.com/chanxuehong/wechat/mp"
+)
+
+const (
+	EventTypeKfCreateSession = "kf_create_session" // 接入会话
+	EventTypeKfCloseSession  = "kf_close_session"  // 关闭会话
+	EventTypeKfSwitchSession = "kf_switch_session" // 转接会话
+)
+
+// 客服接入会话, 微信会把这个事件推送到开发者填写的URL
+type KfCreateSessionEvent struct {
+	XMLName struct{} `xml:"xml" json:"-"`
+	mp.CommonMessageHeader
+
+	Event     string `xml:"Event"     json:"Event"`     // 事件类型, kf_create_session
+	KfAccount string `xml:"KfAccount" json:"KfAccount"` // 完整客服账号，格式为：账号前缀@公众号微信号
+}
+
+func GetKfCreateSessionEvent(msg *mp.MixedMessage) *KfCreateSessionEvent {
+	return &KfCreateSessionEvent{
+		CommonMessageHeader: msg.CommonMessageHeader,
+		Event:               msg.Event,
+		KfAccount:           msg.KfAccount,
+	}
+}
+
+// 客服关闭会话, 微信会把这个事件推送到开发者填写的URL
+type KfCloseSessionEvent struct {
+	XMLName struct{} `xml:"xml" json:"-"`
+	mp.CommonMessageHeader
+
+	Event     string `xml:"Event"     json:"Event"`     // 事件类型, kf_close_session
+	KfAccount string `xml:"KfAccount" json:"KfAccount"` // 完整客服账号，格式为：账号前缀@公众号微信号
+}
+
+func GetKfCloseSessionEvent(msg *mp.MixedMessage) *KfCloseSessionEvent {
+	return &KfCloseSessionEvent{
+		CommonMessageHeader: msg.CommonMessageHeader,
+		Event:               msg.Event,
+		KfAccount:           msg.KfAccount,
+	}
+}
+
+// 客服转接会话, 微信会把这个事件推送到开发者填写的URL
+type KfSwitchSessionEvent struct {
+	XMLName struct{} `xml:"xml" json:"-"`
+	mp.CommonMessageHeader
+
+	Event         string `xml:"Event"         json:"Event"`         // 事件类型, kf_switch_session
+	FromKfAccount string `xml:"FromKfAccount" json:"FromKfAccount"` // 来自的客服账号
+	ToKfAccount   string `xml:"ToKfAccount"   json:"ToKfAccount"`   // 转移给的客服账号
+}
+
+func GetKfSwitchSessionEvent(msg *mp.MixedMessage) *KfSwitchSessionEvent {
+	return &KfSwitchSessionEvent{
+		CommonMessageHeader: msg.CommonMessageHeader,
+		Event:               msg.Event,
+		FromKfAccount:       msg.FromKfAccount,
+		ToKfAccount:         msg.ToKfAccount,
+	}
+}
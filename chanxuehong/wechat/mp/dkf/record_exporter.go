@@ -0,0 +1,331 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package dkf
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+)
+
+// 操作ID（会话状态）对应的操作
+var operCodeActions = map[int]string{
+	1000: "创建未接入会话",
+	1001: "接入会话",
+	1002: "主动发起会话",
+	1004: "关闭会话",
+	1005: "抢接会话",
+	2001: "公众号收到消息",
+	2002: "客服发送消息",
+	2003: "客服收到消息",
+}
+
+// 返回操作ID（会话状态）对应的可读的操作, 未知的操作ID返回 "未知操作(opercode)".
+func OperCodeAction(operCode int) string {
+	if action, ok := operCodeActions[operCode]; ok {
+		return action
+	}
+	return "未知操作(" + strconv.Itoa(operCode) + ")"
+}
+
+const (
+	// 聊天记录导出的格式
+	RecordFormatJSONLines = "jsonl" // 每行一个 JSON 对象
+	RecordFormatCSV       = "csv"
+)
+
+// 聊天记录导出的进度.
+type RecordExportCheckpoint struct {
+	// 进度所属的导出任务, 只能用于恢复相同参数的 Export
+	BeginTime int64  `json:"begin_time"`       // 导出区间的开始时间, unixtime
+	EndTime   int64  `json:"end_time"`         // 导出区间的结束时间, unixtime
+	OpenId    string `json:"openid,omitempty"` // 导出的用户, 为空表示所有用户
+
+	Date      string `json:"date"`       // 正在导出的日期, YYYY-MM-DD 格式
+	PageIndex int    `json:"page_index"` // 该日期已经导出的页数
+}
+
+// 保存的进度不属于本次导出任务(beginTime, endTime, OpenId 不一致).
+var ErrRecordCheckpointMismatch = errors.New("the checkpoint does not match beginTime, endTime or OpenId")
+
+// 聊天记录导出进度的存储接口.
+//  没有保存过进度时 Load 返回 nil, nil.
+type RecordCheckpointStore interface {
+	Load() (*RecordExportCheckpoint, error)
+	Save(*RecordExportCheckpoint) error
+}
+
+// 把进度保存在文件中的 RecordCheckpointStore.
+type FileRecordCheckpointStore string
+
+func (filename FileRecordCheckpointStore) Load() (checkpoint *RecordExportCheckpoint, err error) {
+	data, err := ioutil.ReadFile(string(filename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+
+	checkpoint = new(RecordExportCheckpoint)
+	if err = json.Unmarshal(data, checkpoint); err != nil {
+		checkpoint = nil
+		return
+	}
+	return
+}
+
+func (filename FileRecordCheckpointStore) Save(checkpoint *RecordExportCheckpoint) (err error) {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return
+	}
+
+	// 先写临时文件再重命名, 避免写到一半的时候程序退出
+	tmpFilename := string(filename) + ".tmp"
+	if err = ioutil.WriteFile(tmpFilename, data, 0644); err != nil {
+		return
+	}
+	return os.Rename(tmpFilename, string(filename))
+}
+
+// 聊天记录导出器.
+//  微信的聊天记录查询接口每次查询不能跨日, 导出器把时间区间按天切分, 然后用 RecordIterator 遍历每一天的记录.
+//
+//  exporter := dkf.NewRecordExporter(clt, dkf.RecordFormatCSV)
+//  exporter.CheckpointStore = dkf.FileRecordCheckpointStore("records.checkpoint")
+//  n, err := exporter.Export(file, beginTime, endTime)
+//  if err != nil {
+//      // TODO: 增加你的代码, 以追加模式打开 file, 用相同的参数再次调用 Export 会从上一次的进度继续导出
+//  }
+//
+//  NOTE:
+//  1. 进度是在每一页写入后保存的, 如果程序在写入和保存进度之间退出, 恢复后这一页会被重复导出;
+//  2. 从进度恢复时不会再写入 CSV 的表头, w 必须是追加到上一次导出的同一个文件.
+type RecordExporter struct {
+	wechatClient *Client
+	format       string
+
+	OpenId          string                // 可选; 只导出该用户的聊天记录
+	PageSize        int                   // 每页的记录数, 默认为 50
+	Location        *time.Location        // 按照该时区切分日期, 默认为 time.Local
+	CheckpointStore RecordCheckpointStore // 可选; 保存导出进度
+}
+
+func NewRecordExporter(clt *Client, format string) *RecordExporter {
+	if clt == nil {
+		panic("nil Client")
+	}
+	switch format {
+	case RecordFormatJSONLines, RecordFormatCSV:
+	default:
+		panic("unknown format: " + format)
+	}
+	return &RecordExporter{
+		wechatClient: clt,
+		format:       format,
+		PageSize:     50,
+		Location:     time.Local,
+	}
+}
+
+// 导出的一条聊天记录
+type exportRecord struct {
+	Time     string `json:"time"`
+	Worker   string `json:"worker"`
+	OpenId   string `json:"openid"`
+	OperCode int    `json:"opercode"`
+	Action   string `json:"action"`
+	Text     string `json:"text"`
+}
+
+var recordCSVHeader = []string{"time", "worker", "openid", "opercode", "action", "text"}
+
+// 导出 [beginTime, endTime) 之间的聊天记录到 w, 返回本次导出的记录数.
+//  如果设置了 CheckpointStore 并且有保存的进度, 从保存的进度继续导出, 这时候不会再写入 CSV 的表头,
+//  w 应该以追加模式打开上一次导出的文件; 如果保存的进度和 beginTime, endTime, OpenId 不一致,
+//  返回 ErrRecordCheckpointMismatch, 这时候需要调用者清除保存的进度或者使用其他的 CheckpointStore.
+func (exporter *RecordExporter) Export(w io.Writer, beginTime, endTime time.Time) (n int, err error) {
+	if !beginTime.Before(endTime) {
+		err = errors.New("beginTime must be before endTime")
+		return
+	}
+	pageSize := exporter.PageSize
+	if pageSize <= 0 || pageSize > RecordPageSizeLimit {
+		err = fmt.Errorf("invalid PageSize: %d", pageSize)
+		return
+	}
+	loc := exporter.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	beginTime = beginTime.In(loc)
+	endTime = endTime.In(loc)
+
+	task := RecordExportCheckpoint{
+		BeginTime: beginTime.Unix(),
+		EndTime:   endTime.Unix(),
+		OpenId:    exporter.OpenId,
+	}
+
+	var checkpoint *RecordExportCheckpoint
+	if exporter.CheckpointStore != nil {
+		if checkpoint, err = exporter.CheckpointStore.Load(); err != nil {
+			return
+		}
+		if checkpoint != nil && (checkpoint.BeginTime != task.BeginTime ||
+			checkpoint.EndTime != task.EndTime || checkpoint.OpenId != task.OpenId) {
+			err = ErrRecordCheckpointMismatch
+			return
+		}
+	}
+
+	var csvWriter *csv.Writer
+	if exporter.format == RecordFormatCSV {
+		csvWriter = csv.NewWriter(w)
+		if checkpoint == nil {
+			if err = csvWriter.Write(recordCSVHeader); err != nil {
+				return
+			}
+		}
+	}
+
+	// 从 checkpoint 恢复
+	dayStart := time.Date(beginTime.Year(), beginTime.Month(), beginTime.Day(), 0, 0, 0, 0, loc)
+	skipPages := 0
+	if checkpoint != nil {
+		var date time.Time
+		if date, err = time.ParseInLocation("2006-01-02", checkpoint.Date, loc); err != nil {
+			return
+		}
+		if date.After(dayStart) {
+			dayStart = date
+			skipPages = checkpoint.PageIndex
+		} else if date.Equal(dayStart) {
+			skipPages = checkpoint.PageIndex
+		}
+	}
+
+	for ; dayStart.Before(endTime); dayStart = dayStart.AddDate(0, 0, 1) {
+		dayEnd := dayStart.AddDate(0, 0, 1)
+
+		windowBegin, windowEnd := dayStart, dayEnd
+		if windowBegin.Before(beginTime) {
+			windowBegin = beginTime
+		}
+		if windowEnd.After(endTime) {
+			windowEnd = endTime
+		}
+
+		task.Date = dayStart.Format("2006-01-02")
+		task.PageIndex = skipPages
+
+		var m int
+		m, err = exporter.exportDay(w, csvWriter, task, windowBegin, windowEnd)
+		n += m
+		if err != nil {
+			return
+		}
+		skipPages = 0
+
+		if exporter.CheckpointStore != nil {
+			task.Date = dayEnd.Format("2006-01-02")
+			task.PageIndex = 0
+			if err = exporter.CheckpointStore.Save(&task); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// 导出 checkpoint.Date 这一天的聊天记录, 跳过前面 checkpoint.PageIndex 页.
+func (exporter *RecordExporter) exportDay(w io.Writer, csvWriter *csv.Writer, checkpoint RecordExportCheckpoint,
+	windowBegin, windowEnd time.Time) (n int, err error) {
+
+	request := &GetRecordRequest{
+		StartTime: windowBegin.Unix(),
+		EndTime:   windowEnd.Unix() - 1, // EndTime 是包含的
+		OpenId:    exporter.OpenId,
+		PageSize:  exporter.PageSize,
+		PageIndex: checkpoint.PageIndex + 1,
+	}
+
+	iter, err := exporter.wechatClient.RecordIterator(request)
+	if err != nil {
+		return
+	}
+
+	for iter.HasNext() {
+		var records []Record
+		if records, err = iter.NextPage(); err != nil {
+			return
+		}
+		if len(records) == 0 {
+			break
+		}
+
+		if err = exporter.writeRecords(w, csvWriter, records); err != nil {
+			return
+		}
+		n += len(records)
+		checkpoint.PageIndex++
+
+		if exporter.CheckpointStore != nil {
+			if err = exporter.CheckpointStore.Save(&checkpoint); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (exporter *RecordExporter) writeRecords(w io.Writer, csvWriter *csv.Writer, records []Record) (err error) {
+	loc := exporter.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	if csvWriter != nil {
+		for i := 0; i < len(records); i++ {
+			record := &records[i]
+			if err = csvWriter.Write([]string{
+				time.Unix(record.TimeStamp, 0).In(loc).Format(time.RFC3339),
+				record.Worker,
+				record.OpenId,
+				strconv.Itoa(record.OperCode),
+				OperCodeAction(record.OperCode),
+				record.Text,
+			}); err != nil {
+				return
+			}
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+	}
+
+	encoder := json.NewEncoder(w)
+	for i := 0; i < len(records); i++ {
+		record := &records[i]
+		if err = encoder.Encode(&exportRecord{
+			Time:     time.Unix(record.TimeStamp, 0).In(loc).Format(time.RFC3339),
+			Worker:   record.Worker,
+			OpenId:   record.OpenId,
+			OperCode: record.OperCode,
+			Action:   OperCodeAction(record.OperCode),
+			Text:     record.Text,
+		}); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -5,9 +5,14 @@
 
 package mp
 
-import "fmt"
+import (
+	"fmt"
+	"net"
+	"net/url"
+)
 
 const (
+	ErrCodeSystemBusy        = -1 // 系统繁忙, 此时请开发者稍候再试
 	ErrCodeOK                = 0
 	ErrCodeInvalidCredential = 40001 // access_token 过期（无效）返回这个错误
 	ErrCodeTimeout           = 42001 // access_token 过期（无效）返回这个错误（maybe!!!）
@@ -21,3 +26,19 @@ type Error struct {
 func (e *Error) Error() string {
 	return fmt.Sprintf("errcode: %d, errmsg: %s", e.ErrCode, e.ErrMsg)
 }
+
+// 判断 err 是否表示微信服务器没有接受请求, 这时候重新发送请求不会导致重复执行:
+//  1. 微信服务器返回系统繁忙(errcode == -1);
+//  2. 连接微信服务器失败, 请求还没有发送出去.
+//  其他的网络错误(比如读取回复超时, 连接被重置)不能确定微信服务器是否已经处理了请求.
+func IsRequestNotAccepted(err error) bool {
+	switch e := err.(type) {
+	case *Error:
+		return e.ErrCode == ErrCodeSystemBusy
+	case *url.Error:
+		return IsRequestNotAccepted(e.Err)
+	case *net.OpError:
+		return e.Op == "dial"
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package mp
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsRequestNotAccepted(t *testing.T) {
+	// 连接一个已经关闭的端口, 请求没有发送出去
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	_, dialErr := http.Get("http://" + addr)
+
+	// 服务器读取请求后断开连接, 不能确定请求是否已经被处理
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer srv.Close()
+	_, resetErr := http.Post(srv.URL, "text/plain", nil)
+
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&Error{ErrCode: ErrCodeSystemBusy}, true},
+		{&Error{ErrCode: 45009}, false},
+		{dialErr, true},
+		{resetErr, false},
+		{errors.New("http.Status: 502 Bad Gateway"), false},
+		{nil, false},
+	}
+	for i, tt := range tests {
+		if have := IsRequestNotAccepted(tt.err); have != tt.want {
+			t.Errorf("tests[%d] IsRequestNotAccepted(%v):\nhave %v\nwant %v\n", i, tt.err, have, tt.want)
+		}
+	}
+}
@@ -91,8 +91,9 @@ RETRY:
 	}
 }
 
-// 根据上传的缩略图媒体创建图文消息素材.
-//  articles 的长度不能大于 NewsArticleCountLimit.
+// 根据上传的缩略图媒体创建图文消息素材(media/uploadnews), 群发图文消息应该用这个函数得到的 media_id.
+//  articles 的长度不能大于 NewsArticleCountLimit;
+//  Article.Content 中的图片需要先通过 UploadNewsImage 上传.
 func (clt *Client) CreateNews(articles []Article) (info *MediaInfo, err error) {
 	if len(articles) == 0 {
 		err = errors.New("图文消息是空的")
@@ -127,7 +128,7 @@ func (clt *Client) CreateNews(articles []Article) (info *MediaInfo, err error) {
 	return
 }
 
-// 根据上传的视频文件 media_id 创建视频媒体, 群发视频消息应该用这个函数得到的 media_id.
+// 根据上传的视频文件 media_id 创建视频媒体(media/uploadvideo), 群发视频消息应该用这个函数得到的 media_id.
 //  NOTE: title, description 可以为空.
 func (clt *Client) CreateVideo(mediaId, title, description string) (info *MediaInfo, err error) {
 	var request = struct {
@@ -0,0 +1,61 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package media
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/chanxuehong/wechat/mp"
+)
+
+// 上传图文消息内的图片, 返回图片的 URL.
+//  群发的图文消息(CreateNews)的 Content 中的图片必须用这个接口上传后得到的 URL, 外部的图片链接会被过滤.
+//  图片仅支持jpg/png格式，大小必须在1MB以下。
+func (clt *Client) UploadNewsImage(_filepath string) (url string, err error) {
+	file, err := os.Open(_filepath)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	return clt.uploadNewsImageFromReader(filepath.Base(_filepath), file)
+}
+
+// 上传图文消息内的图片, 返回图片的 URL.
+//  NOTE: 参数 filename 不是文件路径, 是指定 multipart/form-data 里面文件名称
+func (clt *Client) UploadNewsImageFromReader(filename string, reader io.Reader) (url string, err error) {
+	if filename == "" {
+		err = errors.New("empty filename")
+		return
+	}
+	if reader == nil {
+		err = errors.New("nil reader")
+		return
+	}
+	return clt.uploadNewsImageFromReader(filename, reader)
+}
+
+func (clt *Client) uploadNewsImageFromReader(filename string, reader io.Reader) (url string, err error) {
+	var result struct {
+		mp.Error
+		URL string `json:"url"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/cgi-bin/media/uploadimg?access_token="
+	if err = clt.UploadFromReader(incompleteURL, filename, reader, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	url = result.URL
+	return
+}
@@ -0,0 +1,37 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package merchant
+
+import (
+	"github.com/chanxuehong/wechat/mp"
+)
+
+const (
+	EventTypeMerchantOrder = "merchant_order" // 订单付款通知
+)
+
+// 订单付款通知, 用户在微信小店中付款后, 微信会把这个事件推送到开发者填写的URL
+type MerchantOrderEvent struct {
+	XMLName struct{} `xml:"xml" json:"-"`
+	mp.CommonMessageHeader
+
+	Event       string `xml:"Event"       json:"Event"`       // 事件类型, merchant_order
+	OrderId     string `xml:"OrderId"     json:"OrderId"`     // 订单ID
+	OrderStatus int    `xml:"OrderStatus" json:"OrderStatus"` // 订单状态, OrderStatusXXX
+	ProductId   string `xml:"ProductId"   json:"ProductId"`   // 商品ID
+	SKUInfo     string `xml:"SkuInfo"     json:"SkuInfo"`     // sku信息
+}
+
+func GetMerchantOrderEvent(msg *mp.MixedMessage) *MerchantOrderEvent {
+	return &MerchantOrderEvent{
+		CommonMessageHeader: msg.CommonMessageHeader,
+		Event:               msg.Event,
+		OrderId:             msg.OrderId,
+		OrderStatus:         msg.OrderStatus,
+		ProductId:           msg.ProductId,
+		SKUInfo:             msg.SKUInfo,
+	}
+}
@@ -0,0 +1,156 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package merchant
+
+import (
+	"errors"
+
+	"github.com/chanxuehong/wechat/mp"
+)
+
+const (
+	// 订单状态
+	OrderStatusAll        = 0 // 全部状态, 只用于查询
+	OrderStatusNotDeliver = 2 // 待发货
+	OrderStatusDelivered  = 3 // 已发货
+	OrderStatusFinished   = 5 // 已完成
+	OrderStatusRights     = 8 // 维权中
+)
+
+// 订单详情
+type Order struct {
+	OrderId           string `json:"order_id"`            // 订单ID
+	OrderStatus       int    `json:"order_status"`        // 订单状态, OrderStatusXXX
+	OrderTotalPrice   int    `json:"order_total_price"`   // 订单总价格(单位 : 分)
+	OrderCreateTime   int64  `json:"order_create_time"`   // 订单创建时间
+	OrderExpressPrice int    `json:"order_express_price"` // 订单运费价格(单位 : 分)
+	BuyerOpenId       string `json:"buyer_openid"`        // 买家微信OPENID
+	BuyerNick         string `json:"buyer_nick"`          // 买家微信昵称
+	ReceiverName      string `json:"receiver_name"`       // 收货人姓名
+	ReceiverProvince  string `json:"receiver_province"`   // 收货地址省份
+	ReceiverCity      string `json:"receiver_city"`       // 收货地址城市
+	ReceiverZone      string `json:"receiver_zone"`       // 收货地址区/县
+	ReceiverAddress   string `json:"receiver_address"`    // 收货详细地址
+	ReceiverMobile    string `json:"receiver_mobile"`     // 收货人移动电话
+	ReceiverPhone     string `json:"receiver_phone"`      // 收货人固定电话
+	ProductId         string `json:"product_id"`          // 商品ID
+	ProductName       string `json:"product_name"`        // 商品名称
+	ProductPrice      int    `json:"product_price"`       // 商品价格(单位 : 分)
+	ProductSKU        string `json:"product_sku"`         // 商品SKU
+	ProductCount      int    `json:"product_count"`       // 商品个数
+	ProductImg        string `json:"product_img"`         // 商品图片
+	DeliveryId        string `json:"delivery_id"`         // 运单ID
+	DeliveryCompany   string `json:"delivery_company"`    // 物流公司编码
+	TransId           string `json:"trans_id"`            // 交易ID
+}
+
+// 根据订单ID获取订单详情.
+func (clt *Client) OrderGetById(orderId string) (order *Order, err error) {
+	var request = struct {
+		OrderId string `json:"order_id"`
+	}{
+		OrderId: orderId,
+	}
+
+	var result struct {
+		mp.Error
+		Order Order `json:"order"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/merchant/order/getbyid?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	order = &result.Order
+	return
+}
+
+// 根据订单状态/创建时间获取订单详情 请求参数
+type OrderFilter struct {
+	Status    int   `json:"status,omitempty"`    // 可选; 订单状态(不带该字段-全部状态, 2-待发货, 3-已发货, 5-已完成, 8-维权中)
+	BeginTime int64 `json:"begintime,omitempty"` // 可选; 订单创建时间起始时间(不带该字段则不按照时间做筛选)
+	EndTime   int64 `json:"endtime,omitempty"`   // 可选; 订单创建时间终止时间(不带该字段则不按照时间做筛选)
+}
+
+// 根据订单状态/创建时间获取订单详情.
+func (clt *Client) OrderGetByFilter(filter *OrderFilter) (orders []Order, err error) {
+	if filter == nil {
+		filter = &OrderFilter{}
+	}
+
+	var result struct {
+		mp.Error
+		OrderList []Order `json:"order_list"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/merchant/order/getbyfilter?access_token="
+	if err = clt.PostJSON(incompleteURL, filter, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	orders = result.OrderList
+	return
+}
+
+// 设置订单发货信息 请求参数
+type OrderSetDeliveryParameters struct {
+	OrderId         string `json:"order_id"`                    // 订单ID
+	DeliveryCompany string `json:"delivery_company,omitempty"`  // 物流公司ID(参考《物流公司ID》; 当need_delivery为0时，可不填本字段; 当need_delivery为1时，该字段不能为空; 当need_delivery为1且is_others为1时，本字段填写其它物流公司名称)
+	DeliveryTrackNo string `json:"delivery_track_no,omitempty"` // 运单ID(当need_delivery为0时，可不填本字段; 当need_delivery为1时，该字段不能为空)
+	NeedDelivery    int    `json:"need_delivery"`               // 商品是否需要物流(0-不需要，1-需要)
+	IsOthers        int    `json:"is_others"`                   // 是否为6.4.5表之外的其它物流公司(0-否，1-是)
+}
+
+// 设置订单发货信息.
+func (clt *Client) OrderSetDelivery(para *OrderSetDeliveryParameters) (err error) {
+	if para == nil {
+		return errors.New("nil OrderSetDeliveryParameters")
+	}
+
+	var result mp.Error
+
+	incompleteURL := "https://api.weixin.qq.com/merchant/order/setdelivery?access_token="
+	if err = clt.PostJSON(incompleteURL, para, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
+
+// 关闭订单.
+func (clt *Client) OrderClose(orderId string) (err error) {
+	var request = struct {
+		OrderId string `json:"order_id"`
+	}{
+		OrderId: orderId,
+	}
+
+	var result mp.Error
+
+	incompleteURL := "https://api.weixin.qq.com/merchant/order/close?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
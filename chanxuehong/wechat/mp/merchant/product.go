@@ -0,0 +1,256 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package merchant
+
+import (
+	"errors"
+
+	"github.com/chanxuehong/wechat/mp"
+)
+
+const (
+	// 商品状态
+	ProductStatusAll     = 0 // 全部, 只用于查询
+	ProductStatusOnSale  = 1 // 上架
+	ProductStatusOffSale = 2 // 下架
+)
+
+// 商品
+type Product struct {
+	ProductId    string        `json:"product_id,omitempty"`    // 商品ID, 创建的时候不用填写
+	Status       int           `json:"status,omitempty"`        // 商品状态, 查询的时候有返回
+	ProductBase  *ProductBase  `json:"product_base,omitempty"`  // 基本属性
+	SKUList      []SKU         `json:"sku_list,omitempty"`      // sku信息列表
+	AttrExt      *AttrExt      `json:"attrext,omitempty"`       // 可选; 商品其他属性
+	DeliveryInfo *DeliveryInfo `json:"delivery_info,omitempty"` // 运费信息
+}
+
+// 商品的基本属性
+type ProductBase struct {
+	Name       string          `json:"name"`                  // 商品名称
+	CategoryId []string        `json:"category_id"`           // 商品分类id，商品分类列表请通过《获取指定分类的所有子分类》获取
+	MainImg    string          `json:"main_img"`              // 商品主图(图片需调用图片上传接口获得图片Url填写至此，否则无法添加商品。图片分辨率推荐尺寸为640×600)
+	Img        []string        `json:"img,omitempty"`         // 商品图片列表
+	Detail     []ProductDetail `json:"detail,omitempty"`      // 商品详情列表，显示在客户端的商品详情页内
+	DetailHTML string          `json:"detail_html,omitempty"` // 可选; 商品详情页的 html, 查询的时候返回
+	Property   []Property      `json:"property,omitempty"`    // 可选; 商品属性列表
+	SKUInfo    []SKUInfo       `json:"sku_info,omitempty"`    // 可选; 商品sku定义，SKU列表
+	BuyLimit   int             `json:"buy_limit,omitempty"`   // 可选; 用户商品限购数量
+}
+
+// 商品详情, Text 和 Img 只能有一个
+type ProductDetail struct {
+	Text string `json:"text,omitempty"` // 文字描述
+	Img  string `json:"img,omitempty"`  // 图片(图片需调用图片上传接口获得图片Url填写至此，否则无法添加商品)
+}
+
+// 商品属性
+type Property struct {
+	Id  string `json:"id"`  // 属性id
+	VId string `json:"vid"` // 属性值id
+}
+
+// 商品sku定义
+type SKUInfo struct {
+	Id  string   `json:"id"`  // sku属性(SKU列表中id, 支持自定义SKU，格式为"$xxx"，xxx即为显示在客户端中的字符串)
+	VId []string `json:"vid"` // sku值(SKU列表中vid, 如需自定义SKU，格式为"$xxx"，xxx即为显示在客户端中的字符串)
+}
+
+// sku信息
+type SKU struct {
+	SKUId       string `json:"sku_id"`                 // sku信息, 参照上述sku_table的定义; 格式 : "id1:vid1;id2:vid2", 规则 : id_info的组合个数必须与sku_table个数一致(若商品无sku信息, 即商品为统一规格，则此处赋值为空字符串即可)
+	Price       int    `json:"price"`                  // sku微信价(单位 : 分, 微信价必须比原价小, 否则添加商品失败)
+	IconURL     string `json:"icon_url"`               // sku iconurl(图片需调用图片上传接口获得图片Url)
+	Quantity    int    `json:"quantity"`               // sku库存
+	ProductCode string `json:"product_code,omitempty"` // 可选; 商家商品编码
+	OriPrice    int    `json:"ori_price"`              // sku原价(单位 : 分)
+}
+
+// 商品其他属性
+type AttrExt struct {
+	IsPostFree       int       `json:"isPostFree"`         // 是否包邮(0-否, 1-是), 如果包邮delivery_info字段可省略
+	IsHasReceipt     int       `json:"isHasReceipt"`       // 是否提供发票(0-否, 1-是)
+	IsUnderGuaranty  int       `json:"isUnderGuaranty"`    // 是否保修(0-否, 1-是)
+	IsSupportReplace int       `json:"isSupportReplace"`   // 是否支持退换货(0-否, 1-是)
+	Location         *Location `json:"location,omitempty"` // 商品所在地地址
+}
+
+// 商品所在地地址
+type Location struct {
+	Country  string `json:"country"`  // 国家(详见《地区列表》说明)
+	Province string `json:"province"` // 省份(详见《地区列表》说明)
+	City     string `json:"city"`     // 城市(详见《地区列表》说明)
+	Address  string `json:"address"`  // 地址
+}
+
+const (
+	// 运费类型
+	DeliveryTypeExpress  = 0 // 使用下面express字段的默认模板
+	DeliveryTypeTemplate = 1 // 使用template_id代表的邮费模板
+)
+
+// 运费信息
+type DeliveryInfo struct {
+	DeliveryType int       `json:"delivery_type"`         // 运费类型, DeliveryTypeXXX
+	TemplateId   int64     `json:"template_id,omitempty"` // 邮费模板ID
+	Express      []Express `json:"express,omitempty"`     // 快递的运费
+}
+
+// 快递的运费
+type Express struct {
+	Id    int64 `json:"id"`    // 快递ID
+	Price int   `json:"price"` // 运费(单位 : 分)
+}
+
+// 增加商品, 返回商品ID.
+func (clt *Client) ProductCreate(product *Product) (productId string, err error) {
+	if product == nil {
+		err = errors.New("nil Product")
+		return
+	}
+
+	var result struct {
+		mp.Error
+		ProductId string `json:"product_id"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/merchant/create?access_token="
+	if err = clt.PostJSON(incompleteURL, product, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	productId = result.ProductId
+	return
+}
+
+// 删除商品.
+func (clt *Client) ProductDelete(productId string) (err error) {
+	var request = struct {
+		ProductId string `json:"product_id"`
+	}{
+		ProductId: productId,
+	}
+
+	var result mp.Error
+
+	incompleteURL := "https://api.weixin.qq.com/merchant/del?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
+
+// 修改商品.
+//  product.ProductId 必须填写;
+//  从未上架的商品所有信息均可修改，否则商品的名称(name)、商品分类(category)、商品属性(property)这三个字段不可修改。
+func (clt *Client) ProductUpdate(product *Product) (err error) {
+	if product == nil {
+		return errors.New("nil Product")
+	}
+	if product.ProductId == "" {
+		return errors.New("empty product_id")
+	}
+
+	var result mp.Error
+
+	incompleteURL := "https://api.weixin.qq.com/merchant/update?access_token="
+	if err = clt.PostJSON(incompleteURL, product, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
+
+// 查询商品.
+func (clt *Client) ProductGet(productId string) (product *Product, err error) {
+	var request = struct {
+		ProductId string `json:"product_id"`
+	}{
+		ProductId: productId,
+	}
+
+	var result struct {
+		mp.Error
+		ProductInfo Product `json:"product_info"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/merchant/get?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	product = &result.ProductInfo
+	return
+}
+
+// 获取指定状态的所有商品.
+//  status: 商品状态, ProductStatusXXX
+func (clt *Client) ProductGetByStatus(status int) (products []Product, err error) {
+	var request = struct {
+		Status int `json:"status"`
+	}{
+		Status: status,
+	}
+
+	var result struct {
+		mp.Error
+		ProductsInfo []Product `json:"products_info"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/merchant/getbystatus?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	products = result.ProductsInfo
+	return
+}
+
+// 商品上下架.
+//  status: 商品上下架标识, ProductStatusOnSale 或 ProductStatusOffSale
+func (clt *Client) ProductModStatus(productId string, status int) (err error) {
+	var request = struct {
+		ProductId string `json:"product_id"`
+		Status    int    `json:"status"`
+	}{
+		ProductId: productId,
+		Status:    status,
+	}
+
+	var result mp.Error
+
+	incompleteURL := "https://api.weixin.qq.com/merchant/modproductstatus?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
@@ -0,0 +1,190 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package merchant
+
+import (
+	"errors"
+
+	"github.com/chanxuehong/wechat/mp"
+)
+
+// 货架
+type Shelf struct {
+	ShelfId     int64     `json:"shelf_id,omitempty"`     // 货架ID, 添加的时候不用填写
+	ShelfData   ShelfData `json:"shelf_data"`             // 货架详情
+	ShelfBanner string    `json:"shelf_banner,omitempty"` // 货架招牌图片Url(图片需调用图片上传接口获得图片Url填写至此，否则添加货架失败，建议尺寸为640*120，仅控件1-4有banner，控件5没有banner)
+	ShelfName   string    `json:"shelf_name"`             // 货架名称
+}
+
+// 货架详情
+type ShelfData struct {
+	ModuleInfos []ShelfModule `json:"module_infos"` // 控件列表
+}
+
+// 货架控件.
+//  控件1(eid=1): 由一个分组组成, 使用 GroupInfo, 需要填写 GroupInfo.Filter;
+//  控件2(eid=2): 由多个分组组成(分组数量至少两个，最多四个), 使用 GroupInfos;
+//  控件3(eid=3): 由一个分组组成, 使用 GroupInfo, 需要填写 GroupInfo.Img;
+//  控件4(eid=4): 由多个分组组成(分组数量只能为三个), 使用 GroupInfos, 需要填写每个分组的 Img;
+//  控件5(eid=5): 由多个分组组成(分组数量至少两个), 使用 GroupInfos, 需要填写 GroupInfos.ImgBackground.
+type ShelfModule struct {
+	EId        int              `json:"eid"`                   // 控件ID
+	GroupInfo  *ShelfGroupInfo  `json:"group_info,omitempty"`  // 单分组的控件使用
+	GroupInfos *ShelfGroupInfos `json:"group_infos,omitempty"` // 多分组的控件使用
+}
+
+// 货架控件中的分组
+type ShelfGroupInfo struct {
+	GroupId int64        `json:"group_id"`         // 分组ID
+	Filter  *ShelfFilter `json:"filter,omitempty"` // 分组筛选条件
+	Img     string       `json:"img,omitempty"`    // 分组照片(图片需调用图片上传接口获得图片Url填写至此，否则添加货架失败)
+}
+
+// 分组筛选条件
+type ShelfFilter struct {
+	Count int `json:"count"` // 该控件展示商品个数
+}
+
+// 货架控件中的多个分组
+type ShelfGroupInfos struct {
+	Groups        []ShelfGroupInfo `json:"groups"`                   // 分组数组
+	ImgBackground string           `json:"img_background,omitempty"` // 分组照片(图片需调用图片上传接口获得图片Url填写至此，否则添加货架失败，建议分辨率640*1008)
+}
+
+// 增加货架, 返回货架ID.
+func (clt *Client) ShelfAdd(shelf *Shelf) (shelfId int64, err error) {
+	if shelf == nil {
+		err = errors.New("nil Shelf")
+		return
+	}
+
+	var result struct {
+		mp.Error
+		ShelfId int64 `json:"shelf_id"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/merchant/shelf/add?access_token="
+	if err = clt.PostJSON(incompleteURL, shelf, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	shelfId = result.ShelfId
+	return
+}
+
+// 删除货架.
+func (clt *Client) ShelfDelete(shelfId int64) (err error) {
+	var request = struct {
+		ShelfId int64 `json:"shelf_id"`
+	}{
+		ShelfId: shelfId,
+	}
+
+	var result mp.Error
+
+	incompleteURL := "https://api.weixin.qq.com/merchant/shelf/del?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
+
+// 修改货架.
+//  shelf.ShelfId 必须填写.
+func (clt *Client) ShelfModify(shelf *Shelf) (err error) {
+	if shelf == nil {
+		return errors.New("nil Shelf")
+	}
+
+	var result mp.Error
+
+	incompleteURL := "https://api.weixin.qq.com/merchant/shelf/mod?access_token="
+	if err = clt.PostJSON(incompleteURL, shelf, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
+
+// 获取所有货架.
+func (clt *Client) ShelfGetAll() (shelves []Shelf, err error) {
+	var result struct {
+		mp.Error
+		Shelves []struct {
+			ShelfInfo   ShelfData `json:"shelf_info"` // 和 Shelf 的 shelf_data 不一样
+			ShelfBanner string    `json:"shelf_banner"`
+			ShelfName   string    `json:"shelf_name"`
+			ShelfId     int64     `json:"shelf_id"`
+		} `json:"shelves"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/merchant/shelf/getall?access_token="
+	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	shelves = make([]Shelf, len(result.Shelves))
+	for i := 0; i < len(result.Shelves); i++ {
+		shelves[i] = Shelf{
+			ShelfId:     result.Shelves[i].ShelfId,
+			ShelfData:   result.Shelves[i].ShelfInfo,
+			ShelfBanner: result.Shelves[i].ShelfBanner,
+			ShelfName:   result.Shelves[i].ShelfName,
+		}
+	}
+	return
+}
+
+// 根据货架ID获取货架信息.
+func (clt *Client) ShelfGetById(shelfId int64) (shelf *Shelf, err error) {
+	var request = struct {
+		ShelfId int64 `json:"shelf_id"`
+	}{
+		ShelfId: shelfId,
+	}
+
+	var result struct {
+		mp.Error
+		ShelfInfo   ShelfData `json:"shelf_info"`
+		ShelfBanner string    `json:"shelf_banner"`
+		ShelfName   string    `json:"shelf_name"`
+		ShelfId     int64     `json:"shelf_id"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/merchant/shelf/getbyid?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	shelf = &Shelf{
+		ShelfId:     result.ShelfId,
+		ShelfData:   result.ShelfInfo,
+		ShelfBanner: result.ShelfBanner,
+		ShelfName:   result.ShelfName,
+	}
+	return
+}
@@ -0,0 +1,50 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package merchant
+
+import (
+	"github.com/chanxuehong/wechat/mp"
+)
+
+// 增加库存.
+//  productId: 商品ID
+//  skuInfo:   sku信息, 格式"id1:vid1;id2:vid2", 如商品为统一规格，则此处赋值为空字符串即可
+//  quantity:  增加的库存数量
+func (clt *Client) StockAdd(productId, skuInfo string, quantity int) (err error) {
+	return clt.modifyStock("https://api.weixin.qq.com/merchant/stock/add?access_token=", productId, skuInfo, quantity)
+}
+
+// 减少库存.
+//  productId: 商品ID
+//  skuInfo:   sku信息, 格式"id1:vid1;id2:vid2", 如商品为统一规格，则此处赋值为空字符串即可
+//  quantity:  减少的库存数量
+func (clt *Client) StockReduce(productId, skuInfo string, quantity int) (err error) {
+	return clt.modifyStock("https://api.weixin.qq.com/merchant/stock/reduce?access_token=", productId, skuInfo, quantity)
+}
+
+func (clt *Client) modifyStock(incompleteURL, productId, skuInfo string, quantity int) (err error) {
+	var request = struct {
+		ProductId string `json:"product_id"`
+		SKUInfo   string `json:"sku_info"`
+		Quantity  int    `json:"quantity"`
+	}{
+		ProductId: productId,
+		SKUInfo:   skuInfo,
+		Quantity:  quantity,
+	}
+
+	var result mp.Error
+
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
@@ -85,6 +85,72 @@ func (clt *Client) SendNews(msg *News) (err error) {
 	return clt.send(msg)
 }
 
+// 发送客服消息, 图文(图文消息素材).
+func (clt *Client) SendMPNews(msg *MPNews) error {
+	if msg == nil {
+		return errors.New("msg == nil")
+	}
+	return clt.send(msg)
+}
+
+// 发送客服消息, 卡券.
+func (clt *Client) SendWxCard(msg *WxCard) error {
+	if msg == nil {
+		return errors.New("msg == nil")
+	}
+	return clt.send(msg)
+}
+
+// 发送客服消息, 菜单.
+func (clt *Client) SendMsgMenu(msg *MsgMenu) (err error) {
+	if msg == nil {
+		return errors.New("msg == nil")
+	}
+	if err = msg.CheckValid(); err != nil {
+		return
+	}
+	return clt.send(msg)
+}
+
+const (
+	TypingCommandTyping       = "Typing"       // 对用户下发"正在输入"状态
+	TypingCommandCancelTyping = "CancelTyping" // 取消对用户的"正在输入"状态
+)
+
+// 对用户下发"正在输入"状态.
+//  在下发客服消息前调用, 用户在聊天界面会看到"对方正在输入...", 状态持续 15 秒或者直到下发消息.
+func (clt *Client) Typing(toUser string) error {
+	return clt.typing(toUser, TypingCommandTyping)
+}
+
+// 取消对用户的"正在输入"状态.
+func (clt *Client) CancelTyping(toUser string) error {
+	return clt.typing(toUser, TypingCommandCancelTyping)
+}
+
+func (clt *Client) typing(toUser, command string) (err error) {
+	var request = struct {
+		ToUser  string `json:"touser"`
+		Command string `json:"command"`
+	}{
+		ToUser:  toUser,
+		Command: command,
+	}
+
+	var result mp.Error
+
+	incompleteURL := "https://api.weixin.qq.com/cgi-bin/message/custom/typing?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
+
 func (clt *Client) send(msg interface{}) (err error) {
 	var result mp.Error
 
@@ -17,6 +17,10 @@ const (
 	MsgTypeVideo = "video" // 视频消息
 	MsgTypeMusic = "music" // 音乐消息
 	MsgTypeNews  = "news"  // 图文消息
+
+	MsgTypeMPNews  = "mpnews"  // 图文消息, 图文消息素材
+	MsgTypeWxCard  = "wxcard"  // 卡券消息
+	MsgTypeMsgMenu = "msgmenu" // 菜单消息
 )
 
 type CommonMessageHeader struct {
@@ -261,3 +265,116 @@ func (this *News) CheckValid() (err error) {
 	}
 	return
 }
+
+// 图文消息, 发送的是图文消息素材
+type MPNews struct {
+	CommonMessageHeader
+
+	MPNews struct {
+		MediaId string `json:"media_id"` // 图文消息素材的 media_id
+	} `json:"mpnews"`
+
+	*CustomService `json:"customservice,omitempty"`
+}
+
+// 新建图文消息(图文消息素材).
+//  mediaId 是图文消息素材的 media_id;
+//  如果不指定客服则 kfAccount 留空.
+func NewMPNews(toUser, mediaId, kfAccount string) (news *MPNews) {
+	news = &MPNews{
+		CommonMessageHeader: CommonMessageHeader{
+			ToUser:  toUser,
+			MsgType: MsgTypeMPNews,
+		},
+	}
+	news.MPNews.MediaId = mediaId
+
+	if kfAccount != "" {
+		news.CustomService = &CustomService{
+			KfAccount: kfAccount,
+		}
+	}
+	return
+}
+
+// 卡券消息
+type WxCard struct {
+	CommonMessageHeader
+
+	WxCard struct {
+		CardId string `json:"card_id"` // 卡券ID
+	} `json:"wxcard"`
+
+	*CustomService `json:"customservice,omitempty"`
+}
+
+// 新建卡券消息.
+//  如果不指定客服则 kfAccount 留空.
+func NewWxCard(toUser, cardId, kfAccount string) (card *WxCard) {
+	card = &WxCard{
+		CommonMessageHeader: CommonMessageHeader{
+			ToUser:  toUser,
+			MsgType: MsgTypeWxCard,
+		},
+	}
+	card.WxCard.CardId = cardId
+
+	if kfAccount != "" {
+		card.CustomService = &CustomService{
+			KfAccount: kfAccount,
+		}
+	}
+	return
+}
+
+// 菜单消息里的一个选项
+type MenuItem struct {
+	Id      string `json:"id"`      // 选项的 id, 用户点击后会以文本消息 bizmsgmenuid 的形式带回
+	Content string `json:"content"` // 选项的内容
+}
+
+// 菜单消息
+type MsgMenu struct {
+	CommonMessageHeader
+
+	MsgMenu struct {
+		HeadContent string     `json:"head_content,omitempty"` // 菜单上方的文字
+		List        []MenuItem `json:"list"`                   // 菜单选项
+		TailContent string     `json:"tail_content,omitempty"` // 菜单下方的文字
+	} `json:"msgmenu"`
+
+	*CustomService `json:"customservice,omitempty"`
+}
+
+// 新建菜单消息.
+//  headContent, tailContent 可以为 "";
+//  如果不指定客服则 kfAccount 留空.
+func NewMsgMenu(toUser, headContent string, list []MenuItem, tailContent,
+	kfAccount string) (menu *MsgMenu) {
+
+	menu = &MsgMenu{
+		CommonMessageHeader: CommonMessageHeader{
+			ToUser:  toUser,
+			MsgType: MsgTypeMsgMenu,
+		},
+	}
+	menu.MsgMenu.HeadContent = headContent
+	menu.MsgMenu.List = list
+	menu.MsgMenu.TailContent = tailContent
+
+	if kfAccount != "" {
+		menu.CustomService = &CustomService{
+			KfAccount: kfAccount,
+		}
+	}
+	return
+}
+
+// 检查 MsgMenu 是否有效，有效返回 nil，否则返回错误信息.
+func (this *MsgMenu) CheckValid() (err error) {
+	if len(this.MsgMenu.List) <= 0 {
+		err = errors.New("菜单消息没有选项")
+		return
+	}
+	return
+}
@@ -0,0 +1,355 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package mass
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/chanxuehong/util/random"
+)
+
+const (
+	// 群发的对象类型
+	TargetTypeAll   = "all"   // 群发给所有用户, mass2all
+	TargetTypeGroup = "group" // 根据分组群发, mass2group
+	TargetTypeUsers = "users" // 根据 OpenID 列表群发, mass2users
+)
+
+// 群发的对象
+type JobTarget struct {
+	Type    string   `json:"type"`               // TargetTypeXXX
+	GroupId int64    `json:"group_id,omitempty"` // Type == TargetTypeGroup 时有效
+	OpenIds []string `json:"openids,omitempty"`  // Type == TargetTypeUsers 时有效
+}
+
+// 一次群发任务
+type Job struct {
+	JobId      string          `json:"job_id"`      // 任务ID, 由 JobTracker 生成
+	MsgId      int64           `json:"msg_id"`      // 群发接口返回的消息ID
+	Target     JobTarget       `json:"target"`      // 群发的对象
+	Payload    json.RawMessage `json:"payload"`     // 群发的消息体
+	CreateTime int64           `json:"create_time"` // 创建时间, unixtime
+
+	// 下面的字段在收到 MASSSENDJOBFINISH 事件后才有效
+	Finished    bool   `json:"finished"`
+	FinishTime  int64  `json:"finish_time,omitempty"` // 事件的 CreateTime
+	Status      string `json:"status,omitempty"`      // 同 MassSendJobFinishEvent.Status
+	TotalCount  int    `json:"total_count"`
+	FilterCount int    `json:"filter_count"`
+	SentCount   int    `json:"sent_count"`
+	ErrorCount  int    `json:"error_count"`
+
+	Deleted bool `json:"deleted"` // 是否已经调用 DeleteMass 删除
+}
+
+var ErrJobNotFound = errors.New("mass job not found")
+
+// 群发任务的存储接口.
+//  找不到任务时 Get, GetByMsgId 应该返回 ErrJobNotFound.
+type JobStore interface {
+	Save(job *Job) error
+	Get(jobId string) (*Job, error)
+	GetByMsgId(msgid int64) (*Job, error)
+}
+
+var _ JobStore = (*MemoryJobStore)(nil)
+
+// 基于内存的 JobStore, 一般用于测试或者单进程的应用.
+type MemoryJobStore struct {
+	rwmutex  sync.RWMutex
+	jobs     map[string]*Job
+	msgIdMap map[int64]string // msgid --> jobid
+}
+
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{
+		jobs:     make(map[string]*Job),
+		msgIdMap: make(map[int64]string),
+	}
+}
+
+func (store *MemoryJobStore) Save(job *Job) error {
+	if job == nil {
+		return errors.New("nil Job")
+	}
+	copyJob := *job
+
+	store.rwmutex.Lock()
+	store.jobs[job.JobId] = &copyJob
+	store.msgIdMap[job.MsgId] = job.JobId
+	store.rwmutex.Unlock()
+	return nil
+}
+
+func (store *MemoryJobStore) Get(jobId string) (*Job, error) {
+	store.rwmutex.RLock()
+	defer store.rwmutex.RUnlock()
+
+	job, ok := store.jobs[jobId]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	copyJob := *job
+	return &copyJob, nil
+}
+
+func (store *MemoryJobStore) GetByMsgId(msgid int64) (*Job, error) {
+	store.rwmutex.RLock()
+	jobId, ok := store.msgIdMap[msgid]
+	store.rwmutex.RUnlock()
+
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return store.Get(jobId)
+}
+
+// 群发任务跟踪器.
+//  群发接口返回 msgid 后调用 Track 保存任务, 收到 MASSSENDJOBFINISH 事件后调用 HandleFinishEvent 更新任务.
+//
+//  tracker := mass.NewJobTracker(massClient, mass.NewMemoryJobStore())
+//
+//  msg := mass2group.NewText(groupId, "content")
+//  msgid, err := mass2groupClient.SendText(msg)
+//  if err != nil {
+//      // TODO: 增加你的代码
+//  }
+//  job, err := tracker.Track(msgid, &mass.JobTarget{Type: mass.TargetTypeGroup, GroupId: groupId}, msg)
+//
+//  // 消息处理器里面
+//  tracker.HandleFinishEvent(mass.GetMassSendJobFinishEvent(mixedMsg))
+//
+//  NOTE: 同一个任务的更新(HandleFinishEvent, Delete)在本进程内是串行的, 多进程共享 JobStore 时需要 JobStore 自己保证.
+type JobTracker struct {
+	wechatClient *Client
+	store        JobStore
+
+	// 最多暂存的提前到达(Track 之前)的 MASSSENDJOBFINISH 事件数, 默认为 DefaultMaxEarlyEvents
+	MaxEarlyEvents int
+	// 暂存的事件的有效期, 默认为 DefaultEarlyEventTTL
+	EarlyEventTTL time.Duration
+
+	mutex       sync.Mutex
+	locks       map[string]*jobLock         // jobid --> 任务更新的锁
+	earlyEvents map[int64]*earlyFinishEvent // msgid --> 在 Track 之前就收到的事件
+}
+
+const (
+	DefaultMaxEarlyEvents = 1000
+	DefaultEarlyEventTTL  = 10 * time.Minute
+)
+
+type jobLock struct {
+	sync.Mutex
+	refs int // 正在使用或者等待该锁的数量
+}
+
+type earlyFinishEvent struct {
+	event    *MassSendJobFinishEvent
+	received time.Time
+}
+
+func NewJobTracker(clt *Client, store JobStore) *JobTracker {
+	if clt == nil {
+		panic("nil Client")
+	}
+	if store == nil {
+		panic("nil JobStore")
+	}
+	return &JobTracker{
+		wechatClient:   clt,
+		store:          store,
+		MaxEarlyEvents: DefaultMaxEarlyEvents,
+		EarlyEventTTL:  DefaultEarlyEventTTL,
+		locks:          make(map[string]*jobLock),
+		earlyEvents:    make(map[int64]*earlyFinishEvent),
+	}
+}
+
+// 获取任务更新的锁.
+func (tracker *JobTracker) lockJob(jobId string) *jobLock {
+	tracker.mutex.Lock()
+	lock := tracker.locks[jobId]
+	if lock == nil {
+		lock = new(jobLock)
+		tracker.locks[jobId] = lock
+	}
+	lock.refs++
+	tracker.mutex.Unlock()
+
+	lock.Lock()
+	return lock
+}
+
+// 释放任务更新的锁, 没有人使用时删除.
+func (tracker *JobTracker) unlockJob(jobId string, lock *jobLock) {
+	lock.Unlock()
+
+	tracker.mutex.Lock()
+	if lock.refs--; lock.refs == 0 {
+		delete(tracker.locks, jobId)
+	}
+	tracker.mutex.Unlock()
+}
+
+// 在任务的锁内读取, 修改并保存任务; fn 返回 false 时不保存.
+func (tracker *JobTracker) update(jobId string, fn func(job *Job) (save bool, err error)) (job *Job, err error) {
+	lock := tracker.lockJob(jobId)
+	defer tracker.unlockJob(jobId, lock)
+
+	if job, err = tracker.store.Get(jobId); err != nil {
+		return
+	}
+	save, err := fn(job)
+	if err != nil || !save {
+		return
+	}
+	if err = tracker.store.Save(job); err != nil {
+		job = nil
+		return
+	}
+	return
+}
+
+// 保存一次群发任务, 如果已经收到了该任务的 MASSSENDJOBFINISH 事件, 同时用事件更新任务.
+//  msgid:   群发接口返回的消息ID
+//  target:  群发的对象
+//  payload: 群发的消息, 比如 *mass2group.Text, 会被 json 序列化后保存
+func (tracker *JobTracker) Track(msgid int64, target *JobTarget, payload interface{}) (job *Job, err error) {
+	if target == nil {
+		err = errors.New("nil JobTarget")
+		return
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	id := random.NewId()
+	job = &Job{
+		JobId:      hex.EncodeToString(id[:]),
+		MsgId:      msgid,
+		Target:     *target,
+		Payload:    payloadBytes,
+		CreateTime: time.Now().Unix(),
+	}
+	// 保存和取出暂存的事件在同一个锁内, 避免和 HandleFinishEvent 交错而丢失事件
+	tracker.mutex.Lock()
+	if err = tracker.store.Save(job); err != nil {
+		tracker.mutex.Unlock()
+		job = nil
+		return
+	}
+	early := tracker.earlyEvents[msgid]
+	delete(tracker.earlyEvents, msgid)
+	tracker.mutex.Unlock()
+
+	if early != nil {
+		return tracker.finish(job.JobId, early.event)
+	}
+	return
+}
+
+// 根据 MASSSENDJOBFINISH 事件更新对应的群发任务.
+//  如果事件对应的任务不存在返回 ErrJobNotFound, 这时候事件会被暂存(最多 MaxEarlyEvents 个, 有效期 EarlyEventTTL),
+//  之后 Track 该 msgid 的时候再更新任务, 因为事件可能比群发接口的返回先到达.
+func (tracker *JobTracker) HandleFinishEvent(event *MassSendJobFinishEvent) (job *Job, err error) {
+	if event == nil {
+		err = errors.New("nil MassSendJobFinishEvent")
+		return
+	}
+
+	job, err = tracker.store.GetByMsgId(event.MsgId)
+	if err == ErrJobNotFound {
+		tracker.mutex.Lock()
+		// 再查询一次, Track 可能在两次查询之间保存了任务
+		if job, err = tracker.store.GetByMsgId(event.MsgId); err == ErrJobNotFound {
+			tracker.addEarlyEvent(event)
+		}
+		tracker.mutex.Unlock()
+	}
+	if err != nil {
+		return
+	}
+	return tracker.finish(job.JobId, event)
+}
+
+// 暂存提前到达的事件, 调用者需要持有 tracker.mutex.
+func (tracker *JobTracker) addEarlyEvent(event *MassSendJobFinishEvent) {
+	now := time.Now()
+	for msgid, early := range tracker.earlyEvents {
+		if now.Sub(early.received) > tracker.EarlyEventTTL {
+			delete(tracker.earlyEvents, msgid)
+		}
+	}
+	if len(tracker.earlyEvents) >= tracker.MaxEarlyEvents {
+		return
+	}
+	tracker.earlyEvents[event.MsgId] = &earlyFinishEvent{
+		event:    event,
+		received: now,
+	}
+}
+
+// 用 MASSSENDJOBFINISH 事件更新任务.
+func (tracker *JobTracker) finish(jobId string, event *MassSendJobFinishEvent) (job *Job, err error) {
+	return tracker.update(jobId, func(job *Job) (bool, error) {
+		job.Finished = true
+		job.FinishTime = event.CreateTime
+		job.Status = event.Status
+		job.TotalCount = event.TotalCount
+		job.FilterCount = event.FilterCount
+		job.SentCount = event.SentCount
+		job.ErrorCount = event.ErrorCount
+		return true, nil
+	})
+}
+
+// 群发任务的状态
+type JobStatus struct {
+	*Job
+	MsgStatus string `json:"msg_status"` // GetMassStatus 返回的状态, 比如 SEND_SUCCESS
+}
+
+// 查询群发任务的状态, 结合了 GetMassStatus 的结果和 MASSSENDJOBFINISH 事件的数据.
+func (tracker *JobTracker) Status(jobId string) (status *JobStatus, err error) {
+	job, err := tracker.store.Get(jobId)
+	if err != nil {
+		return
+	}
+
+	massStatus, err := tracker.wechatClient.GetMassStatus(job.MsgId)
+	if err != nil {
+		return
+	}
+
+	status = &JobStatus{
+		Job:       job,
+		MsgStatus: massStatus.Status,
+	}
+	return
+}
+
+// 删除群发任务对应的群发消息.
+//  只有已经发送成功的图文消息和视频消息才能删除, 见 Client.DeleteMass.
+func (tracker *JobTracker) Delete(jobId string) (err error) {
+	_, err = tracker.update(jobId, func(job *Job) (bool, error) {
+		if job.Deleted {
+			return false, nil
+		}
+		if err := tracker.wechatClient.DeleteMass(job.MsgId); err != nil {
+			return false, err
+		}
+		job.Deleted = true
+		return true, nil
+	})
+	return
+}
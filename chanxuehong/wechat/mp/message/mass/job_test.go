@@ -0,0 +1,69 @@
+package mass
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestJobTrackerEarlyFinishEvent(t *testing.T) {
+	tracker := NewJobTracker(&Client{}, NewMemoryJobStore())
+
+	event := &MassSendJobFinishEvent{MsgId: 1000001625, Status: "send success", TotalCount: 100, SentCount: 99, ErrorCount: 1}
+	if _, err := tracker.HandleFinishEvent(event); err != ErrJobNotFound {
+		t.Fatalf("HandleFinishEvent before Track: have %v, want %v", err, ErrJobNotFound)
+	}
+
+	job, err := tracker.Track(1000001625, &JobTarget{Type: TargetTypeAll}, map[string]string{"content": "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !job.Finished || job.SentCount != 99 || job.ErrorCount != 1 {
+		t.Errorf("Track did not apply the early event: %+v", job)
+	}
+	if job, err = tracker.store.Get(job.JobId); err != nil || !job.Finished {
+		t.Errorf("stored job: %+v, %v", job, err)
+	}
+
+	tracker.MaxEarlyEvents = 1
+	tracker.HandleFinishEvent(&MassSendJobFinishEvent{MsgId: 1})
+	tracker.HandleFinishEvent(&MassSendJobFinishEvent{MsgId: 2})
+	if n := len(tracker.earlyEvents); n != 1 {
+		t.Errorf("early events: have %d, want 1", n)
+	}
+}
+
+func TestJobTrackerConcurrentUpdate(t *testing.T) {
+	tracker := NewJobTracker(&Client{}, NewMemoryJobStore())
+	job, err := tracker.Track(1000001626, &JobTarget{Type: TargetTypeAll}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 并发的更新不能互相覆盖
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			tracker.finish(job.JobId, &MassSendJobFinishEvent{MsgId: 1000001626, SentCount: 10})
+		}()
+		go func() {
+			defer wg.Done()
+			tracker.update(job.JobId, func(job *Job) (bool, error) {
+				job.Deleted = true
+				return true, nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if job, err = tracker.store.Get(job.JobId); err != nil {
+		t.Fatal(err)
+	}
+	if !job.Deleted || !job.Finished || job.SentCount != 10 {
+		t.Errorf("job: %+v", job)
+	}
+	if len(tracker.locks) != 0 {
+		t.Errorf("locks leaked: %v", tracker.locks)
+	}
+}
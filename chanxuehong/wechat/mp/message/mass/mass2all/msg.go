@@ -6,11 +6,12 @@
 package mass2all
 
 const (
-	MsgTypeText  = "text"
-	MsgTypeImage = "image"
-	MsgTypeVoice = "voice"
-	MsgTypeVideo = "mpvideo"
-	MsgTypeNews  = "mpnews"
+	MsgTypeText   = "text"
+	MsgTypeImage  = "image"
+	MsgTypeVoice  = "voice"
+	MsgTypeVideo  = "mpvideo"
+	MsgTypeNews   = "mpnews"
+	MsgTypeWxCard = "wxcard"
 )
 
 type CommonMessageHeader struct {
@@ -99,3 +100,20 @@ func NewNews(mediaId string) *News {
 	msg.News.MediaId = mediaId
 	return &msg
 }
+
+// 卡券消息
+type WxCard struct {
+	CommonMessageHeader
+	WxCard struct {
+		CardId string `json:"card_id"`
+	} `json:"wxcard"`
+}
+
+// 新建卡券消息
+func NewWxCard(cardId string) *WxCard {
+	var msg WxCard
+	msg.MsgType = MsgTypeWxCard
+	msg.Filter.IsToAll = true
+	msg.WxCard.CardId = cardId
+	return &msg
+}
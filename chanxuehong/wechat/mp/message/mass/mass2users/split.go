@@ -0,0 +1,242 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package mass2users
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/chanxuehong/wechat/mp"
+	"github.com/chanxuehong/wechat/mp/message/mass/preview"
+)
+
+const ToUserCountMinLimit = 2 // 根据 OpenID 列表群发, 每次至少要有 2 个用户
+
+// 把 openids 分成若干批, 每批的长度在 [ToUserCountMinLimit, ToUserCountLimit] 之间.
+//  为了避免最后一批只剩 1 个用户, 每批的长度是平均分配的, 比如 10001 个用户会分成 5001 和 5000 两批.
+//  len(openids) < ToUserCountMinLimit 时返回 nil, 这种情况只能用预览接口发送.
+func SplitOpenIds(openids []string) (batches [][]string) {
+	n := len(openids)
+	if n < ToUserCountMinLimit {
+		return
+	}
+
+	batchCount := (n + ToUserCountLimit - 1) / ToUserCountLimit
+	batches = make([][]string, 0, batchCount)
+	for i := 0; i < batchCount; i++ {
+		// 前 n%batchCount 批多分 1 个
+		size := n / batchCount
+		if i < n%batchCount {
+			size++
+		}
+		batches = append(batches, openids[:size:size])
+		openids = openids[size:]
+	}
+	return
+}
+
+// 可以分批群发的消息, Text, Image, Voice, Video, News, WxCard 都实现了这个接口.
+type Message interface {
+	GetToUser() []string
+	SetToUser(toUser []string)
+}
+
+func (header *CommonMessageHeader) GetToUser() []string {
+	return header.ToUser
+}
+
+func (header *CommonMessageHeader) SetToUser(toUser []string) {
+	header.ToUser = toUser
+}
+
+// 一批发送失败的用户
+type BatchFailure struct {
+	OpenIds []string
+	Err     error
+
+	// 网络错误(比如读取回复超时), 不能确定微信服务器是否已经群发给这批用户,
+	// 为了避免重复群发, 这种情况不会重试, 调用者需要自己确认(比如通过 MASSSENDJOBFINISH 事件)后再决定是否重新发送.
+	OutcomeUnknown bool
+}
+
+// 分批群发的结果
+type SplitSendResult struct {
+	MsgIds   []int64        // 发送成功的每一批的消息ID
+	Failures []BatchFailure // 发送失败的批次
+}
+
+// 分批群发器
+//
+//  sender := mass2users.NewSplitSender(clt)
+//  result := sender.Send(openids, mass2users.NewText(nil, "content"))
+//  for _, failure := range result.Failures {
+//      if failure.OutcomeUnknown {
+//          // TODO: 增加你的代码, 不能确定是否已经群发, 不要直接重新发送
+//      }
+//      // TODO: 增加你的代码
+//  }
+type SplitSender struct {
+	wechatClient *Client
+
+	MaxRetries    int           // 每批因为系统繁忙或者连接失败而失败后的最大重试次数, 默认为 2
+	RetryInterval time.Duration // 重试的间隔, 每次重试间隔翻倍, 默认为 1 秒
+
+	// 只有 1 个用户时是否用预览接口发送, 默认为 false, 这时候返回错误.
+	//  NOTE: 预览接口每日调用次数有限制, 并且只支持 Text, Image, Voice, Video, News, WxCard.
+	UsePreviewForSingleUser bool
+}
+
+func NewSplitSender(clt *Client) *SplitSender {
+	if clt == nil {
+		panic("nil Client")
+	}
+	return &SplitSender{
+		wechatClient:  clt,
+		MaxRetries:    2,
+		RetryInterval: time.Second,
+	}
+}
+
+// 把 msg 分批发送给 openids 中的用户, msg 的 ToUser 会被忽略.
+//  发送完成后 msg 的 ToUser 会被恢复为原来的值.
+func (sender *SplitSender) Send(openids []string, msg Message) (result *SplitSendResult) {
+	result = &SplitSendResult{}
+
+	if msg == nil {
+		result.Failures = append(result.Failures, BatchFailure{
+			OpenIds: openids,
+			Err:     errors.New("msg == nil"),
+		})
+		return
+	}
+	if len(openids) == 0 {
+		return
+	}
+
+	if len(openids) < ToUserCountMinLimit {
+		clt, recorder := sender.recordingClient()
+		msgid, err := sender.sendPreview(clt, openids[0], msg)
+		if err != nil {
+			result.Failures = append(result.Failures, BatchFailure{
+				OpenIds:        openids,
+				Err:            err,
+				OutcomeUnknown: recorder.issued && isOutcomeUnknown(err),
+			})
+			return
+		}
+		result.MsgIds = append(result.MsgIds, msgid)
+		return
+	}
+
+	oldToUser := msg.GetToUser()
+	defer msg.SetToUser(oldToUser)
+
+	for _, batch := range SplitOpenIds(openids) {
+		msg.SetToUser(batch)
+		msgid, issued, err := sender.sendWithRetry(msg)
+		if err != nil {
+			result.Failures = append(result.Failures, BatchFailure{
+				OpenIds:        batch,
+				Err:            err,
+				OutcomeUnknown: issued && isOutcomeUnknown(err),
+			})
+			continue
+		}
+		result.MsgIds = append(result.MsgIds, msgid)
+	}
+	return
+}
+
+// 发送一批, issued 表示最后一次发送是否已经发出了 HTTP 请求.
+func (sender *SplitSender) sendWithRetry(msg Message) (msgid int64, issued bool, err error) {
+	interval := sender.RetryInterval
+	for i := 0; ; i++ {
+		clt, recorder := sender.recordingClient()
+		msgid, err = clt.send(msg)
+		issued = recorder.issued
+		if err == nil {
+			return
+		}
+		if i >= sender.MaxRetries || !mp.IsRequestNotAccepted(err) {
+			return
+		}
+		if interval > 0 {
+			time.Sleep(interval)
+			interval *= 2
+		}
+	}
+}
+
+// 已经发出 HTTP 请求后的错误是否不能确定结果.
+//  微信服务器返回的错误和连接失败都可以确定结果, 其他的错误(比如读取回复超时)不能确定微信服务器是否已经处理了请求.
+//  发出请求之前的错误(比如参数错误, 获取 access_token 失败)都可以确定没有发送, 调用者不要用这个函数判断.
+func isOutcomeUnknown(err error) bool {
+	if _, ok := err.(*mp.Error); ok {
+		return false
+	}
+	return !mp.IsRequestNotAccepted(err)
+}
+
+// 记录是否发出过 HTTP 请求的 http.RoundTripper
+type requestRecorder struct {
+	transport http.RoundTripper
+	issued    bool
+}
+
+func (recorder *requestRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder.issued = true
+	return recorder.transport.RoundTrip(req)
+}
+
+// 返回一个和 sender.wechatClient 一样的 Client, 通过 recorder 可以知道这个 Client 是否发出过 HTTP 请求.
+//  用来区分发送之前的错误和发送之后的错误.
+func (sender *SplitSender) recordingClient() (clt *Client, recorder *requestRecorder) {
+	httpClient := *http.DefaultClient
+	if sender.wechatClient.HttpClient != nil {
+		httpClient = *sender.wechatClient.HttpClient
+	}
+	recorder = &requestRecorder{transport: httpClient.Transport}
+	if recorder.transport == nil {
+		recorder.transport = http.DefaultTransport
+	}
+	httpClient.Transport = recorder
+
+	clt = &Client{WechatClient: sender.wechatClient.WechatClient}
+	clt.HttpClient = &httpClient
+	return
+}
+
+// 用预览接口把 msg 发送给单个用户.
+func (sender *SplitSender) sendPreview(mclt *Client, openid string, msg Message) (msgid int64, err error) {
+	if !sender.UsePreviewForSingleUser {
+		err = fmt.Errorf("根据 OpenID 列表群发至少需要 %d 个用户", ToUserCountMinLimit)
+		return
+	}
+
+	clt := preview.Client{
+		WechatClient: mclt.WechatClient,
+	}
+
+	switch v := msg.(type) {
+	case *Text:
+		return clt.SendText(preview.NewText(openid, v.Text.Content))
+	case *Image:
+		return clt.SendImage(preview.NewImage(openid, v.Image.MediaId))
+	case *Voice:
+		return clt.SendVoice(preview.NewVoice(openid, v.Voice.MediaId))
+	case *Video:
+		return clt.SendVideo(preview.NewVideo(openid, v.Video.MediaId))
+	case *News:
+		return clt.SendNews(preview.NewNews(openid, v.News.MediaId))
+	case *WxCard:
+		return clt.SendWxCard(preview.NewWxCard(openid, v.WxCard.CardId, ""))
+	default:
+		err = fmt.Errorf("预览接口不支持的消息类型: %T", msg)
+		return
+	}
+}
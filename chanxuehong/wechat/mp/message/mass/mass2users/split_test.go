@@ -0,0 +1,138 @@
+package mass2users
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/chanxuehong/wechat/internal/testutil"
+	"github.com/chanxuehong/wechat/mp"
+)
+
+type testTokenServer struct{}
+
+func (testTokenServer) Token() (string, error)        { return "ACCESS_TOKEN", nil }
+func (testTokenServer) TokenRefresh() (string, error) { return "ACCESS_TOKEN", nil }
+
+// 创建一个请求都转发到 addr 的 SplitSender
+func newTestSplitSender(addr string) *SplitSender {
+	sender := NewSplitSender(NewClient(testTokenServer{}, testutil.NewRedirectHttpClient(addr)))
+	sender.RetryInterval = 0
+	return sender
+}
+
+func TestSplitOpenIds(t *testing.T) {
+	tests := []struct {
+		n     int
+		sizes []int
+	}{
+		{0, nil},
+		{1, nil},
+		{2, []int{2}},
+		{ToUserCountLimit, []int{ToUserCountLimit}},
+		{ToUserCountLimit + 1, []int{ToUserCountLimit/2 + 1, ToUserCountLimit / 2}},
+	}
+	for _, test := range tests {
+		batches := SplitOpenIds(make([]string, test.n))
+		if len(batches) != len(test.sizes) {
+			t.Errorf("SplitOpenIds(%d): have %d batches, want %d", test.n, len(batches), len(test.sizes))
+			continue
+		}
+		for i, batch := range batches {
+			if len(batch) != test.sizes[i] {
+				t.Errorf("SplitOpenIds(%d)[%d]: have %d, want %d", test.n, i, len(batch), test.sizes[i])
+			}
+		}
+	}
+}
+
+// 发送之前的错误可以确定没有发送
+func TestSplitSenderLocalError(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "msg_id": 1})
+	}))
+	defer srv.Close()
+	sender := newTestSplitSender(srv.Listener.Addr().String())
+
+	result := sender.Send([]string{"openid"}, NewText(nil, "content"))
+	if len(result.Failures) != 1 || result.Failures[0].OutcomeUnknown {
+		t.Errorf("single user: %+v", result.Failures)
+	}
+
+	sender.UsePreviewForSingleUser = true
+	result = sender.Send([]string{"openid"}, &struct{ CommonMessageHeader }{})
+	if len(result.Failures) != 1 || result.Failures[0].OutcomeUnknown {
+		t.Errorf("unsupported message: %+v", result.Failures)
+	}
+
+	result = sender.Send([]string{"openid", "openid2"}, nil)
+	if len(result.Failures) != 1 || result.Failures[0].OutcomeUnknown {
+		t.Errorf("nil message: %+v", result.Failures)
+	}
+
+	if requests := atomic.LoadInt32(&requests); requests != 0 {
+		t.Errorf("requests: have %d, want 0", requests)
+	}
+}
+
+// 发出请求之后的网络错误不能确定结果, 并且不重试
+func TestSplitSenderOutcomeUnknown(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		conn.Close() // 不回复直接断开连接
+	}))
+	defer srv.Close()
+	sender := newTestSplitSender(srv.Listener.Addr().String())
+
+	result := sender.Send([]string{"openid", "openid2"}, NewText(nil, "content"))
+	if len(result.Failures) != 1 || !result.Failures[0].OutcomeUnknown {
+		t.Errorf("Failures: %+v", result.Failures)
+	}
+	if requests := atomic.LoadInt32(&requests); requests != 1 {
+		t.Errorf("requests: have %d, want 1", requests)
+	}
+}
+
+// 系统繁忙和连接失败可以确定结果, 并且会重试
+func TestSplitSenderRetry(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		json.NewEncoder(w).Encode(mp.Error{ErrCode: mp.ErrCodeSystemBusy, ErrMsg: "system error"})
+	}))
+	defer srv.Close()
+	sender := newTestSplitSender(srv.Listener.Addr().String())
+
+	result := sender.Send([]string{"openid", "openid2"}, NewText(nil, "content"))
+	if len(result.Failures) != 1 || result.Failures[0].OutcomeUnknown {
+		t.Errorf("system busy: %+v", result.Failures)
+	}
+	if requests := atomic.LoadInt32(&requests); int(requests) != sender.MaxRetries+1 {
+		t.Errorf("requests: have %d, want %d", requests, sender.MaxRetries+1)
+	}
+
+	// 连接一个已经关闭的端口
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	sender = newTestSplitSender(addr)
+	result = sender.Send([]string{"openid", "openid2"}, NewText(nil, "content"))
+	if len(result.Failures) != 1 || result.Failures[0].OutcomeUnknown {
+		t.Errorf("dial failed: %+v", result.Failures)
+	}
+}
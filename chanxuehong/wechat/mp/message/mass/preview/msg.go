@@ -6,11 +6,12 @@
 package preview
 
 const (
-	MsgTypeText  = "text"
-	MsgTypeImage = "image"
-	MsgTypeVoice = "voice"
-	MsgTypeVideo = "mpvideo"
-	MsgTypeNews  = "mpnews"
+	MsgTypeText   = "text"
+	MsgTypeImage  = "image"
+	MsgTypeVoice  = "voice"
+	MsgTypeVideo  = "mpvideo"
+	MsgTypeNews   = "mpnews"
+	MsgTypeWxCard = "wxcard"
 )
 
 type CommonMessageHeader struct {
@@ -97,3 +98,23 @@ func NewNews(touser, mediaId string) *News {
 	msg.News.MediaId = mediaId
 	return &msg
 }
+
+// 卡券消息
+type WxCard struct {
+	CommonMessageHeader
+	WxCard struct {
+		CardId  string `json:"card_id"`
+		CardExt string `json:"card_ext,omitempty"` // 可选; 卡券的 card_ext 字段, JSON 字符串, 见卡券签名
+	} `json:"wxcard"`
+}
+
+// 新建卡券消息
+//  NOTE: cardExt 可以为空
+func NewWxCard(touser, cardId, cardExt string) *WxCard {
+	var msg WxCard
+	msg.MsgType = MsgTypeWxCard
+	msg.ToUser = touser
+	msg.WxCard.CardId = cardId
+	msg.WxCard.CardExt = cardExt
+	return &msg
+}
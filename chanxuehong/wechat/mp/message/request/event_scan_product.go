@@ -0,0 +1,122 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package request
+
+import (
+	"github.com/chanxuehong/wechat/mp"
+)
+
+const (
+	// 扫一扫商品事件推送
+	EventTypeUserScanProduct             = "user_scan_product"               // 打开商品主页事件推送
+	EventTypeUserScanProductEnterSession = "user_scan_product_enter_session" // 进入公众号事件推送
+	EventTypeUserScanProductAsync        = "user_scan_product_async"         // 地理位置信息异步推送
+	EventTypeUserScanProductVerifyAction = "user_scan_product_verify_action" // 商品审核结果推送
+)
+
+const (
+	// 商品审核结果
+	ScanProductVerifyResultOK      = "verify_ok"       // 审核通过
+	ScanProductVerifyResultNotPass = "verify_not_pass" // 审核未通过
+)
+
+// 打开商品主页事件推送
+type UserScanProductEvent struct {
+	XMLName struct{} `xml:"xml" json:"-"`
+	mp.CommonMessageHeader
+
+	Event       string `xml:"Event"       json:"Event"`       // 事件类型, user_scan_product
+	KeyStandard string `xml:"KeyStandard" json:"KeyStandard"` // 商品编码标准
+	KeyStr      string `xml:"KeyStr"      json:"KeyStr"`      // 商品编码内容
+	Country     string `xml:"Country"     json:"Country"`     // 用户在微信内设置的国家
+	Province    string `xml:"Province"    json:"Province"`    // 用户在微信内设置的省份
+	City        string `xml:"City"        json:"City"`        // 用户在微信内设置的城市
+	Sex         int    `xml:"Sex"         json:"Sex"`         // 用户的性别, 1为男性, 2为女性, 0代表未知
+	Scene       int    `xml:"Scene"       json:"Scene"`       // 打开商品主页的场景, 1为扫码, 2为其他打开场景(如会话、收藏或朋友圈)
+	ExtInfo     string `xml:"ExtInfo"     json:"ExtInfo"`     // 调用"获取商品二维码接口"时传入的extinfo, 为标识参数
+}
+
+func GetUserScanProductEvent(msg *mp.MixedMessage) *UserScanProductEvent {
+	return &UserScanProductEvent{
+		CommonMessageHeader: msg.CommonMessageHeader,
+		Event:               msg.Event,
+		KeyStandard:         msg.KeyStandard,
+		KeyStr:              msg.KeyStr,
+		Country:             msg.Country,
+		Province:            msg.Province,
+		City:                msg.City,
+		Sex:                 msg.Sex,
+		Scene:               msg.Scene,
+		ExtInfo:             msg.ExtInfo,
+	}
+}
+
+// 进入公众号事件推送
+type UserScanProductEnterSessionEvent struct {
+	XMLName struct{} `xml:"xml" json:"-"`
+	mp.CommonMessageHeader
+
+	Event       string `xml:"Event"       json:"Event"`       // 事件类型, user_scan_product_enter_session
+	KeyStandard string `xml:"KeyStandard" json:"KeyStandard"` // 商品编码标准
+	KeyStr      string `xml:"KeyStr"      json:"KeyStr"`      // 商品编码内容
+	ExtInfo     string `xml:"ExtInfo"     json:"ExtInfo"`     // 调用"获取商品二维码接口"时传入的extinfo, 为标识参数
+}
+
+func GetUserScanProductEnterSessionEvent(msg *mp.MixedMessage) *UserScanProductEnterSessionEvent {
+	return &UserScanProductEnterSessionEvent{
+		CommonMessageHeader: msg.CommonMessageHeader,
+		Event:               msg.Event,
+		KeyStandard:         msg.KeyStandard,
+		KeyStr:              msg.KeyStr,
+		ExtInfo:             msg.ExtInfo,
+	}
+}
+
+// 地理位置信息异步推送
+type UserScanProductAsyncEvent struct {
+	XMLName struct{} `xml:"xml" json:"-"`
+	mp.CommonMessageHeader
+
+	Event       string `xml:"Event"       json:"Event"`       // 事件类型, user_scan_product_async
+	KeyStandard string `xml:"KeyStandard" json:"KeyStandard"` // 商品编码标准
+	KeyStr      string `xml:"KeyStr"      json:"KeyStr"`      // 商品编码内容
+	ExtInfo     string `xml:"ExtInfo"     json:"ExtInfo"`     // 调用"获取商品二维码接口"时传入的extinfo, 为标识参数
+	RegionCode  string `xml:"RegionCode"  json:"RegionCode"`  // 用户的实时地理位置信息(目前只精确到省一级), 可在国家统计局网站查到对应明细
+}
+
+func GetUserScanProductAsyncEvent(msg *mp.MixedMessage) *UserScanProductAsyncEvent {
+	return &UserScanProductAsyncEvent{
+		CommonMessageHeader: msg.CommonMessageHeader,
+		Event:               msg.Event,
+		KeyStandard:         msg.KeyStandard,
+		KeyStr:              msg.KeyStr,
+		ExtInfo:             msg.ExtInfo,
+		RegionCode:          msg.RegionCode,
+	}
+}
+
+// 商品审核结果推送
+type UserScanProductVerifyActionEvent struct {
+	XMLName struct{} `xml:"xml" json:"-"`
+	mp.CommonMessageHeader
+
+	Event       string `xml:"Event"       json:"Event"`       // 事件类型, user_scan_product_verify_action
+	KeyStandard string `xml:"KeyStandard" json:"KeyStandard"` // 商品编码标准
+	KeyStr      string `xml:"KeyStr"      json:"KeyStr"`      // 商品编码内容
+	Result      string `xml:"Result"      json:"Result"`      // 审核结果, ScanProductVerifyResultXXX
+	ReasonMsg   string `xml:"ReasonMsg"   json:"ReasonMsg"`   // 审核未通过的原因
+}
+
+func GetUserScanProductVerifyActionEvent(msg *mp.MixedMessage) *UserScanProductVerifyActionEvent {
+	return &UserScanProductVerifyActionEvent{
+		CommonMessageHeader: msg.CommonMessageHeader,
+		Event:               msg.Event,
+		KeyStandard:         msg.KeyStandard,
+		KeyStr:              msg.KeyStr,
+		Result:              msg.Result,
+		ReasonMsg:           msg.ReasonMsg,
+	}
+}
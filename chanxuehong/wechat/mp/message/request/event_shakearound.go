@@ -0,0 +1,47 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package request
+
+import (
+	"github.com/chanxuehong/wechat/mp"
+)
+
+const (
+	EventTypeShakearoundUserShake = "ShakearoundUserShake" // 摇一摇事件通知
+)
+
+// 摇一摇的 iBeacon 设备信息
+type Beacon struct {
+	UUID     string  `xml:"Uuid"     json:"Uuid"`
+	Major    int     `xml:"Major"    json:"Major"`
+	Minor    int     `xml:"Minor"    json:"Minor"`
+	Distance float64 `xml:"Distance" json:"Distance"` // 设备与用户的距离(浮点数; 单位: 米)
+}
+
+// 摇一摇事件通知
+type ShakearoundUserShakeEvent struct {
+	XMLName struct{} `xml:"xml" json:"-"`
+	mp.CommonMessageHeader
+
+	Event         string   `xml:"Event"                               json:"Event"`                    // 事件类型, ShakearoundUserShake
+	ChosenBeacon  Beacon   `xml:"ChosenBeacon"                        json:"ChosenBeacon"`             // 用户摇到的设备
+	AroundBeacons []Beacon `xml:"AroundBeacons>AroundBeacon,omitempty" json:"AroundBeacons,omitempty"` // 摇到的周边其他设备列表
+}
+
+func GetShakearoundUserShakeEvent(msg *mp.MixedMessage) *ShakearoundUserShakeEvent {
+	event := &ShakearoundUserShakeEvent{
+		CommonMessageHeader: msg.CommonMessageHeader,
+		Event:               msg.Event,
+		ChosenBeacon:        Beacon(msg.ChosenBeacon),
+	}
+	if n := len(msg.AroundBeacons); n > 0 {
+		event.AroundBeacons = make([]Beacon, n)
+		for i := 0; i < n; i++ {
+			event.AroundBeacons[i] = Beacon(msg.AroundBeacons[i])
+		}
+	}
+	return event
+}
@@ -0,0 +1,98 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package request
+
+import (
+	"github.com/chanxuehong/wechat/mp"
+)
+
+const (
+	// 微信认证事件推送
+	EventTypeQualificationVerifySuccess = "qualification_verify_success" // 资质认证成功(此时立即获得接口权限)
+	EventTypeQualificationVerifyFail    = "qualification_verify_fail"    // 资质认证失败
+	EventTypeNamingVerifySuccess        = "naming_verify_success"        // 名称认证成功(即命名成功)
+	EventTypeNamingVerifyFail           = "naming_verify_fail"           // 名称认证失败(这时虽然客户端不打勾, 但仍有接口权限)
+	EventTypeAnnualRenew                = "annual_renew"                 // 年审通知
+	EventTypeVerifyExpired              = "verify_expired"               // 认证过期失效通知
+)
+
+// 资质认证成功 / 名称认证成功 / 年审通知 / 认证过期失效通知
+type VerifyExpiredTimeEvent struct {
+	XMLName struct{} `xml:"xml" json:"-"`
+	mp.CommonMessageHeader
+
+	Event       string `xml:"Event"       json:"Event"`       // 事件类型
+	ExpiredTime int64  `xml:"ExpiredTime" json:"ExpiredTime"` // 有效期(整形), 指的是时间戳, 将于该时间戳认证过期(对于 verify_expired 则是已经过期的时间戳)
+}
+
+func GetVerifyExpiredTimeEvent(msg *mp.MixedMessage) *VerifyExpiredTimeEvent {
+	return &VerifyExpiredTimeEvent{
+		CommonMessageHeader: msg.CommonMessageHeader,
+		Event:               msg.Event,
+		ExpiredTime:         msg.ExpiredTime,
+	}
+}
+
+// 资质认证成功
+type QualificationVerifySuccessEvent VerifyExpiredTimeEvent
+
+func GetQualificationVerifySuccessEvent(msg *mp.MixedMessage) *QualificationVerifySuccessEvent {
+	return (*QualificationVerifySuccessEvent)(GetVerifyExpiredTimeEvent(msg))
+}
+
+// 名称认证成功
+type NamingVerifySuccessEvent VerifyExpiredTimeEvent
+
+func GetNamingVerifySuccessEvent(msg *mp.MixedMessage) *NamingVerifySuccessEvent {
+	return (*NamingVerifySuccessEvent)(GetVerifyExpiredTimeEvent(msg))
+}
+
+// 年审通知, ExpiredTime 为认证过期时间, 需在该时间前进行年审
+type AnnualRenewEvent VerifyExpiredTimeEvent
+
+func GetAnnualRenewEvent(msg *mp.MixedMessage) *AnnualRenewEvent {
+	return (*AnnualRenewEvent)(GetVerifyExpiredTimeEvent(msg))
+}
+
+// 认证过期失效通知
+type VerifyExpiredEvent VerifyExpiredTimeEvent
+
+func GetVerifyExpiredEvent(msg *mp.MixedMessage) *VerifyExpiredEvent {
+	return (*VerifyExpiredEvent)(GetVerifyExpiredTimeEvent(msg))
+}
+
+// 资质认证失败 / 名称认证失败
+type VerifyFailEvent struct {
+	XMLName struct{} `xml:"xml" json:"-"`
+	mp.CommonMessageHeader
+
+	Event      string `xml:"Event"      json:"Event"`      // 事件类型
+	FailTime   int64  `xml:"FailTime"   json:"FailTime"`   // 失败发生时间(整形), 时间戳
+	FailReason string `xml:"FailReason" json:"FailReason"` // 认证失败的原因
+}
+
+func GetVerifyFailEvent(msg *mp.MixedMessage) *VerifyFailEvent {
+	return &VerifyFailEvent{
+		CommonMessageHeader: msg.CommonMessageHeader,
+		Event:               msg.Event,
+		FailTime:            msg.FailTime,
+		FailReason:          msg.FailReason,
+	}
+}
+
+// 资质认证失败
+type QualificationVerifyFailEvent VerifyFailEvent
+
+func GetQualificationVerifyFailEvent(msg *mp.MixedMessage) *QualificationVerifyFailEvent {
+	return (*QualificationVerifyFailEvent)(GetVerifyFailEvent(msg))
+}
+
+// 名称认证失败
+type NamingVerifyFailEvent VerifyFailEvent
+
+func GetNamingVerifyFailEvent(msg *mp.MixedMessage) *NamingVerifyFailEvent {
+	return (*NamingVerifyFailEvent)(GetVerifyFailEvent(msg))
+}
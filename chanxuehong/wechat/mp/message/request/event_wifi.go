@@ -0,0 +1,39 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package request
+
+import (
+	"github.com/chanxuehong/wechat/mp"
+)
+
+const (
+	EventTypeWifiConnected = "WifiConnected" // Wi-Fi连网成功事件
+)
+
+// Wi-Fi连网成功事件
+type WifiConnectedEvent struct {
+	XMLName struct{} `xml:"xml" json:"-"`
+	mp.CommonMessageHeader
+
+	Event       string `xml:"Event"       json:"Event"`       // 事件类型, WifiConnected
+	ConnectTime int64  `xml:"ConnectTime" json:"ConnectTime"` // 连网时间
+	ExpireTime  int64  `xml:"ExpireTime"  json:"ExpireTime"`  // 系统保留字段, 固定值
+	VendorId    string `xml:"VendorId"    json:"VendorId"`    // 系统保留字段, 固定值
+	ShopId      int64  `xml:"ShopId"      json:"ShopId"`      // 门店ID, 即 shop_id
+	DeviceNo    string `xml:"DeviceNo"    json:"DeviceNo"`    // 连网的设备无线mac地址, 对应bssid
+}
+
+func GetWifiConnectedEvent(msg *mp.MixedMessage) *WifiConnectedEvent {
+	return &WifiConnectedEvent{
+		CommonMessageHeader: msg.CommonMessageHeader,
+		Event:               msg.Event,
+		ConnectTime:         msg.ConnectTime,
+		ExpireTime:          msg.ExpireTime,
+		VendorId:            msg.VendorId,
+		ShopId:              msg.ShopId,
+		DeviceNo:            msg.DeviceNo,
+	}
+}
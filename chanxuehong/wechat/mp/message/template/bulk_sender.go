@@ -0,0 +1,365 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package template
+
+import (
+	"sync"
+	"time"
+
+	"github.com/chanxuehong/wechat/mp"
+)
+
+const (
+	// 批量发送时单个用户的发送状态, 除此之外还有 TemplateSendStatusXXX 这几种最终状态
+	BulkStatusSent    = "sent"    // 已经调用接口发送成功, 等待 TEMPLATESENDJOBFINISH 事件
+	BulkStatusSkipped = "skipped" // 因为用户原因(比如用户未关注)发送失败, 不影响其他用户的发送
+	BulkStatusFailed  = "failed"  // 发送失败
+	BulkStatusUnknown = "unknown" // 网络错误, 不能确定微信服务器是否已经发送, 为了避免重复发送不会重试
+	BulkStatusAborted = "aborted" // 已经从 msgs 读取, 但是因为中止发送而没有发送
+)
+
+// 默认的用户级别的错误码, 这些错误只影响当前用户, 不会中止批量发送.
+var DefaultSkipErrCodes = []int{
+	40003, // 不合法的 OpenID
+	43004, // 需要接收者关注
+	43019, // 需要将接收者从黑名单中移除
+	43101, // 用户拒绝接受消息
+}
+
+// 批量发送的一条消息
+type BulkMessage struct {
+	OpenId string
+	Msg    *TemplateMessage // Msg.ToUser 会被设置为 OpenId
+}
+
+// 单个用户的发送记录
+type BulkRecord struct {
+	OpenId string `json:"openid"`
+	MsgId  int64  `json:"msgid,omitempty"`
+	Status string `json:"status"`          // BulkStatusXXX 或者 TemplateSendStatusXXX
+	Error  string `json:"error,omitempty"` // 发送失败的原因
+}
+
+// 批量发送的报告
+type BulkReport struct {
+	Records []BulkRecord   `json:"records"`
+	Count   map[string]int `json:"count"` // 各个状态的数量
+}
+
+// 模板消息批量发送器.
+//  用令牌桶限制发送速率, 并且限制并发数; 发送成功后记录 msgid 到 openid 的对应关系,
+//  最终的送达状态需要把收到的 TEMPLATESENDJOBFINISH 事件交给 HandleJobFinishEvent 来更新.
+//
+//  sender := template.NewBulkSender(clt, 100, 10)
+//  report, err := sender.Send(msgChan)
+//  if err != nil {
+//      // TODO: 增加你的代码, 这是中止发送的错误, report 包含了已经处理的消息,
+//      // 状态为 BulkStatusAborted 的消息和 msgChan 中剩下的消息都没有发送
+//  }
+//
+//  // 消息处理器里面
+//  sender.HandleJobFinishEvent(template.GetTemplateSendJobFinishEvent(mixedMsg))
+//
+//  // 稍后获取最终的报告
+//  report = sender.Report()
+type BulkSender struct {
+	wechatClient *Client
+
+	rate        float64 // 每秒发送的消息数
+	concurrency int     // 并发数
+
+	// 用户级别的错误码, 默认为 DefaultSkipErrCodes
+	SkipErrCodes []int
+
+	// 微信服务器没有接受请求(系统繁忙或者连接失败)时的最大重试次数, 默认为 2
+	MaxRetries int
+	// 重试的间隔, 每次重试间隔翻倍, 默认为 1 秒
+	RetryInterval time.Duration
+	// 最多暂存的提前到达的事件数, 默认为 DefaultMaxEarlyEvents
+	MaxEarlyEvents int
+
+	mutex       sync.Mutex
+	records     []*BulkRecord         // 按照发送完成的顺序
+	msgIdMap    map[int64]*BulkRecord // msgid --> record
+	earlyEvents map[int64]string      // 在记录 msgid 之前就收到的事件, msgid --> status
+	sending     int                   // 正在运行的 Send 的数量, 只有这时候才暂存事件
+}
+
+const DefaultMaxEarlyEvents = 10000
+
+// 创建批量发送器.
+//  rate:        每秒最多发送的消息数, 必须大于 0
+//  concurrency: 同时进行的 http 请求数, 小于等于 0 时为 1
+func NewBulkSender(clt *Client, rate float64, concurrency int) *BulkSender {
+	if clt == nil {
+		panic("nil Client")
+	}
+	if rate <= 0 {
+		panic("rate must be greater than 0")
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &BulkSender{
+		wechatClient:   clt,
+		rate:           rate,
+		concurrency:    concurrency,
+		SkipErrCodes:   DefaultSkipErrCodes,
+		MaxRetries:     2,
+		RetryInterval:  time.Second,
+		MaxEarlyEvents: DefaultMaxEarlyEvents,
+		msgIdMap:       make(map[int64]*BulkRecord),
+		earlyEvents:    make(map[int64]string),
+	}
+}
+
+func (sender *BulkSender) isSkipErrCode(code int) bool {
+	for _, c := range sender.SkipErrCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// 发送 msgs 中的所有消息, 直到 msgs 被关闭或者遇到中止发送的错误.
+//  1. SkipErrCodes 的错误只影响当前用户, 状态为 BulkStatusSkipped;
+//  2. 系统繁忙或者连接失败会重试 MaxRetries 次, 仍然失败则中止发送;
+//  3. 其他的网络错误不能确定是否已经发送, 状态为 BulkStatusUnknown, 不会重试也不会中止发送;
+//  4. 其他微信服务器返回的错误会中止发送.
+//  中止发送时已经读取但是没有发送的消息状态为 BulkStatusAborted, msgs 中剩下的消息不会被读取,
+//  调用者需要自己停止往 msgs 写入数据.
+func (sender *BulkSender) Send(msgs <-chan *BulkMessage) (report *BulkReport, err error) {
+	sender.mutex.Lock()
+	sender.sending++
+	sender.mutex.Unlock()
+
+	defer func() {
+		sender.mutex.Lock()
+		sender.sending--
+		if sender.sending == 0 {
+			// 所有的 msgid 都已经记录, 剩下的事件不是本发送器发送的消息的
+			sender.earlyEvents = make(map[int64]string)
+		}
+		sender.mutex.Unlock()
+	}()
+
+	bucket := newTokenBucket(sender.rate, sender.concurrency)
+
+	var (
+		fatalOnce sync.Once
+		fatalErr  error
+		stop      = make(chan struct{})
+	)
+	setFatal := func(e error) {
+		fatalOnce.Do(func() {
+			fatalErr = e
+			close(stop)
+		})
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(sender.concurrency)
+	for i := 0; i < sender.concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				var bm *BulkMessage
+				var ok bool
+				select {
+				case <-stop:
+					return
+				case bm, ok = <-msgs:
+					if !ok {
+						return
+					}
+				}
+				if bm == nil || bm.Msg == nil {
+					continue
+				}
+
+				bucket.Wait()
+				select {
+				case <-stop:
+					sender.addRecord(&BulkRecord{OpenId: bm.OpenId, Status: BulkStatusAborted})
+					return
+				default:
+				}
+
+				if e := sender.send(bm, stop); e != nil {
+					setFatal(e)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	report = sender.Report()
+	err = fatalErr
+	return
+}
+
+// 发送一条消息并且记录结果, 返回中止发送的错误.
+//  stop 被关闭的时候不再重试.
+func (sender *BulkSender) send(bm *BulkMessage, stop <-chan struct{}) (fatalErr error) {
+	msg := *bm.Msg
+	msg.ToUser = bm.OpenId
+
+	record := &BulkRecord{
+		OpenId: bm.OpenId,
+	}
+	defer sender.addRecord(record)
+
+	interval := sender.RetryInterval
+	for i := 0; ; i++ {
+		msgid, err := sender.wechatClient.Send(&msg)
+		if err == nil {
+			record.MsgId = msgid
+			record.Status = BulkStatusSent
+			return
+		}
+		record.Error = err.Error()
+
+		if e, ok := err.(*mp.Error); ok && sender.isSkipErrCode(e.ErrCode) {
+			record.Status = BulkStatusSkipped
+			return
+		}
+		if !mp.IsRequestNotAccepted(err) {
+			if _, ok := err.(*mp.Error); ok {
+				record.Status = BulkStatusFailed
+				fatalErr = err
+				return
+			}
+			record.Status = BulkStatusUnknown
+			return
+		}
+		if i >= sender.MaxRetries {
+			record.Status = BulkStatusFailed
+			fatalErr = err
+			return
+		}
+
+		select {
+		case <-stop:
+			record.Status = BulkStatusAborted
+			return
+		case <-time.After(interval):
+			interval *= 2
+		}
+	}
+}
+
+// 记录一个用户的发送结果.
+func (sender *BulkSender) addRecord(record *BulkRecord) {
+	sender.mutex.Lock()
+	defer sender.mutex.Unlock()
+
+	sender.records = append(sender.records, record)
+	if record.Status == BulkStatusSent {
+		if status, ok := sender.earlyEvents[record.MsgId]; ok {
+			record.Status = status
+			delete(sender.earlyEvents, record.MsgId)
+		}
+		sender.msgIdMap[record.MsgId] = record
+	}
+}
+
+// 根据 TEMPLATESENDJOBFINISH 事件更新对应用户的发送状态.
+//  如果事件的 msgid 不是本发送器发送的, 返回 false.
+//  NOTE: 事件可能比 Send 记录 msgid 先到达, 这时候会暂存事件并返回 false, 等记录 msgid 的时候再更新状态;
+//  只有 Send 正在运行的时候才暂存事件, 并且最多暂存 MaxEarlyEvents 个, Send 返回后会丢弃剩下的事件.
+func (sender *BulkSender) HandleJobFinishEvent(event *TemplateSendJobFinishEvent) (ok bool) {
+	if event == nil {
+		return
+	}
+
+	sender.mutex.Lock()
+	defer sender.mutex.Unlock()
+
+	record, ok := sender.msgIdMap[event.MsgId]
+	if !ok {
+		if sender.sending > 0 && len(sender.earlyEvents) < sender.MaxEarlyEvents {
+			sender.earlyEvents[event.MsgId] = event.Status
+		}
+		return
+	}
+	record.Status = event.Status
+	return
+}
+
+// 查询 msgid 对应的发送记录.
+func (sender *BulkSender) Record(msgid int64) (record BulkRecord, ok bool) {
+	sender.mutex.Lock()
+	defer sender.mutex.Unlock()
+
+	p, ok := sender.msgIdMap[msgid]
+	if !ok {
+		return
+	}
+	record = *p
+	return
+}
+
+// 获取当前的发送报告, 会反映已经收到的 TEMPLATESENDJOBFINISH 事件.
+func (sender *BulkSender) Report() *BulkReport {
+	sender.mutex.Lock()
+	defer sender.mutex.Unlock()
+
+	report := &BulkReport{
+		Records: make([]BulkRecord, len(sender.records)),
+		Count:   make(map[string]int),
+	}
+	for i, record := range sender.records {
+		report.Records[i] = *record
+		report.Count[record.Status]++
+	}
+	return report
+}
+
+// 令牌桶, 以 rate 的速率产生令牌, 最多保存 burst 个令牌.
+type tokenBucket struct {
+	mutex  sync.Mutex
+	rate   float64   // 每秒产生的令牌数
+	burst  float64   // 桶的容量
+	tokens float64   // 当前的令牌数
+	last   time.Time // 上一次计算令牌的时间
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// 获取一个令牌, 没有令牌的时候阻塞等待.
+func (bucket *tokenBucket) Wait() {
+	bucket.mutex.Lock()
+	now := time.Now()
+	bucket.tokens += now.Sub(bucket.last).Seconds() * bucket.rate
+	if bucket.tokens > bucket.burst {
+		bucket.tokens = bucket.burst
+	}
+	bucket.last = now
+
+	// 预先扣除令牌, 不足的部分通过等待来补齐
+	bucket.tokens--
+	var wait time.Duration
+	if bucket.tokens < 0 {
+		wait = time.Duration(-bucket.tokens / bucket.rate * float64(time.Second))
+	}
+	bucket.mutex.Unlock()
+
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
@@ -114,3 +114,16 @@ func (clt *Client) Send(msg *TemplateMessage) (msgid int64, err error) {
 	msgid = result.MsgId
 	return
 }
+
+// 检查模板消息的 data 和模板是否匹配, 匹配的话发送模板消息.
+//  tpl 一般是通过 GetAllPrivateTemplate 获取的模板.
+func (clt *Client) SendWithTemplate(msg *TemplateMessage, tpl *Template) (msgid int64, err error) {
+	if tpl == nil {
+		err = errors.New("nil Template")
+		return
+	}
+	if err = tpl.CheckMessage(msg); err != nil {
+		return
+	}
+	return clt.Send(msg)
+}
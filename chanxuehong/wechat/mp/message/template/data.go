@@ -0,0 +1,141 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package template
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 模板消息 data 中的一项
+type DataItem struct {
+	Value string `json:"value"`
+	Color string `json:"color,omitempty"`
+}
+
+// 模板消息的 data, key 对应模板内容中的 {{key.DATA}}
+//
+//  data := template.Data{}
+//  data.Set("first", "恭喜你购买成功！", "#173177")
+//  data.Set("keynote1", "巧克力", "")
+//  msg, err := tpl.NewMessage("touser", "http://weixin.qq.com/download", data)
+//  if err != nil {
+//      // TODO: 增加你的代码, 一般是 key 写错了
+//  }
+type Data map[string]DataItem
+
+// 设置 key 对应的值, color 可以为空.
+func (data Data) Set(key, value, color string) Data {
+	data[key] = DataItem{
+		Value: value,
+		Color: color,
+	}
+	return data
+}
+
+// 数据和模板不匹配的错误
+type DataMismatchError struct {
+	TemplateId  string
+	UnknownKeys []string // data 中有但是模板中没有的 key
+	MissingKeys []string // 模板中有但是 data 中没有的 key
+}
+
+func (e *DataMismatchError) Error() string {
+	var parts []string
+	if len(e.UnknownKeys) > 0 {
+		parts = append(parts, "unknown keys: "+strings.Join(e.UnknownKeys, ", "))
+	}
+	if len(e.MissingKeys) > 0 {
+		parts = append(parts, "missing keys: "+strings.Join(e.MissingKeys, ", "))
+	}
+	return fmt.Sprintf("template %s: data does not match: %s", e.TemplateId, strings.Join(parts, "; "))
+}
+
+// 检查 keys 是否和模板的 {{key.DATA}} 占位符一一对应.
+func (tpl *Template) checkKeys(keys []string) error {
+	tplKeys := tpl.Keys()
+
+	tplKeySet := make(map[string]bool, len(tplKeys))
+	for _, key := range tplKeys {
+		tplKeySet[key] = true
+	}
+	keySet := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		keySet[key] = true
+	}
+
+	var e DataMismatchError
+	for _, key := range keys {
+		if !tplKeySet[key] {
+			e.UnknownKeys = append(e.UnknownKeys, key)
+		}
+	}
+	for _, key := range tplKeys {
+		if !keySet[key] {
+			e.MissingKeys = append(e.MissingKeys, key)
+		}
+	}
+	if len(e.UnknownKeys) == 0 && len(e.MissingKeys) == 0 {
+		return nil
+	}
+	sort.Strings(e.UnknownKeys)
+	e.TemplateId = tpl.TemplateId
+	return &e
+}
+
+// 检查 data 的 key 是否和模板的 {{key.DATA}} 占位符一一对应.
+//  不匹配时返回 *DataMismatchError.
+func (tpl *Template) CheckData(data Data) error {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	return tpl.checkKeys(keys)
+}
+
+// 检查 msg 的 RawJSONData 的 key 是否和模板的 {{key.DATA}} 占位符一一对应.
+//  不匹配时返回 *DataMismatchError.
+func (tpl *Template) CheckMessage(msg *TemplateMessage) error {
+	if msg == nil {
+		return errors.New("nil TemplateMessage")
+	}
+	if msg.TemplateId != tpl.TemplateId {
+		return fmt.Errorf("template_id mismatch, have %s, want %s", msg.TemplateId, tpl.TemplateId)
+	}
+
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(msg.RawJSONData, &data); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	return tpl.checkKeys(keys)
+}
+
+// 根据模板创建模板消息, 会检查 data 是否和模板匹配.
+func (tpl *Template) NewMessage(toUser, url string, data Data) (msg *TemplateMessage, err error) {
+	if err = tpl.CheckData(data); err != nil {
+		return
+	}
+
+	rawJSONData, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+
+	msg = &TemplateMessage{
+		ToUser:      toUser,
+		TemplateId:  tpl.TemplateId,
+		URL:         url,
+		RawJSONData: rawJSONData,
+	}
+	return
+}
@@ -0,0 +1,114 @@
+// @description wechat 是腾讯微信公众平台 api 的 golang 语言封装
+// @link        https://github.com/chanxuehong/wechat for the canonical source repository
+// @license     https://github.com/chanxuehong/wechat/blob/master/LICENSE
+// @authors     chanxuehong(chanxuehong@gmail.com)
+
+package template
+
+import (
+	"regexp"
+
+	"github.com/chanxuehong/wechat/mp"
+)
+
+// 账号下的一个模板
+type Template struct {
+	TemplateId      string `json:"template_id"`      // 模板ID
+	Title           string `json:"title"`            // 模板标题
+	PrimaryIndustry string `json:"primary_industry"` // 模板所属行业的一级行业
+	DeputyIndustry  string `json:"deputy_industry"`  // 模板所属行业的二级行业
+	Content         string `json:"content"`          // 模板内容
+	Example         string `json:"example"`          // 模板示例
+}
+
+// 匹配模板内容中的 {{key.DATA}}
+var templateKeyRegexp = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\.DATA\s*\}\}`)
+
+// 解析模板内容中的 {{key.DATA}} 占位符, 按照出现的顺序返回 key 的列表, 重复的 key 只返回一次.
+func (tpl *Template) Keys() (keys []string) {
+	matches := templateKeyRegexp.FindAllStringSubmatch(tpl.Content, -1)
+	if len(matches) == 0 {
+		return
+	}
+
+	keys = make([]string, 0, len(matches))
+	seen := make(map[string]bool, len(matches))
+	for _, match := range matches {
+		key := match[1]
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return
+}
+
+// 获取模板列表.
+func (clt *Client) GetAllPrivateTemplate() (templateList []Template, err error) {
+	var result struct {
+		mp.Error
+		TemplateList []Template `json:"template_list"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/cgi-bin/template/get_all_private_template?access_token="
+	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	templateList = result.TemplateList
+	return
+}
+
+// 删除模板.
+func (clt *Client) DelPrivateTemplate(templateId string) (err error) {
+	var request = struct {
+		TemplateId string `json:"template_id"`
+	}{
+		TemplateId: templateId,
+	}
+
+	var result mp.Error
+
+	incompleteURL := "https://api.weixin.qq.com/cgi-bin/template/del_private_template?access_token="
+	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
+
+type Industry struct {
+	FirstClass  string `json:"first_class"`  // 主行业
+	SecondClass string `json:"second_class"` // 副行业
+}
+
+// 获取设置的行业信息.
+func (clt *Client) GetIndustry() (primary, secondary *Industry, err error) {
+	var result struct {
+		mp.Error
+		PrimaryIndustry   Industry `json:"primary_industry"`
+		SecondaryIndustry Industry `json:"secondary_industry"`
+	}
+
+	incompleteURL := "https://api.weixin.qq.com/cgi-bin/template/get_industry?access_token="
+	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != mp.ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	primary = &result.PrimaryIndustry
+	secondary = &result.SecondaryIndustry
+	return
+}
@@ -139,4 +139,49 @@ type MixedMessage struct {
 	FriendUserName string `xml:"FriendUserName" json:"FriendUserName"`
 	UserCardCode   string `xml:"UserCardCode"   json:"UserCardCode"`
 	OuterId        int64  `xml:"OuterId"        json:"OuterId"`
+	ModifyBonus    int    `xml:"ModifyBonus"    json:"ModifyBonus"`
+	ModifyBalance  int    `xml:"ModifyBalance"  json:"ModifyBalance"`
+
+	KfAccount     string `xml:"KfAccount"     json:"KfAccount"`
+	FromKfAccount string `xml:"FromKfAccount" json:"FromKfAccount"`
+	ToKfAccount   string `xml:"ToKfAccount"   json:"ToKfAccount"`
+
+	ExpiredTime int64  `xml:"ExpiredTime" json:"ExpiredTime"`
+	FailTime    int64  `xml:"FailTime"    json:"FailTime"`
+	FailReason  string `xml:"FailReason"  json:"FailReason"`
+
+	UniqId string `xml:"UniqId" json:"UniqId"`
+	PoiId  int64  `xml:"PoiId"  json:"PoiId"`
+	Result string `xml:"Result" json:"Result"`
+	Msg    string `xml:"Msg"    json:"Msg"`
+
+	ConnectTime int64  `xml:"ConnectTime" json:"ConnectTime"`
+	ExpireTime  int64  `xml:"ExpireTime"  json:"ExpireTime"`
+	VendorId    string `xml:"VendorId"    json:"VendorId"`
+	ShopId      int64  `xml:"ShopId"      json:"ShopId"`
+	DeviceNo    string `xml:"DeviceNo"    json:"DeviceNo"`
+
+	KeyStandard string `xml:"KeyStandard" json:"KeyStandard"`
+	KeyStr      string `xml:"KeyStr"      json:"KeyStr"`
+	Country     string `xml:"Country"     json:"Country"`
+	Province    string `xml:"Province"    json:"Province"`
+	City        string `xml:"City"        json:"City"`
+	Sex         int    `xml:"Sex"         json:"Sex"`
+	Scene       int    `xml:"Scene"       json:"Scene"`
+	ExtInfo     string `xml:"ExtInfo"     json:"ExtInfo"`
+	RegionCode  string `xml:"RegionCode"  json:"RegionCode"`
+	ReasonMsg   string `xml:"ReasonMsg"   json:"ReasonMsg"`
+
+	ChosenBeacon struct {
+		UUID     string  `xml:"Uuid"     json:"Uuid"`
+		Major    int     `xml:"Major"    json:"Major"`
+		Minor    int     `xml:"Minor"    json:"Minor"`
+		Distance float64 `xml:"Distance" json:"Distance"`
+	} `xml:"ChosenBeacon" json:"ChosenBeacon"`
+	AroundBeacons []struct {
+		UUID     string  `xml:"Uuid"     json:"Uuid"`
+		Major    int     `xml:"Major"    json:"Major"`
+		Minor    int     `xml:"Minor"    json:"Minor"`
+		Distance float64 `xml:"Distance" json:"Distance"`
+	} `xml:"AroundBeacons>AroundBeacon,omitempty" json:"AroundBeacons,omitempty"`
 }